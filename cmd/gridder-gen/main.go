@@ -0,0 +1,152 @@
+// Command gridder-gen generates puzzles without the game window, for making
+// puzzle packs. Puzzles are written as JSON lines that the game loads with
+// -puzzle, picking one with -record, or as text grids for printing.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/prizelobby/union-gridder/core"
+)
+
+// Record is one generated puzzle in the JSON lines output. The puzzle fields
+// are at the top level, so each line can be loaded by the game on its own.
+type Record struct {
+	core.Puzzle
+	Seed       uint32 `json:"seed"`
+	Code       string `json:"code,omitempty"`
+	Difficulty string `json:"difficulty"`
+}
+
+var (
+	count      = flag.Int("count", 1, "number of puzzles to generate")
+	seed       = flag.Int64("seed", -1, "first seed; puzzles use seeds seed to seed+count-1 (default random seeds)")
+	rows       = flag.Int("rows", core.DEFAULT_ROWS, "grid rows")
+	cols       = flag.Int("cols", core.DEFAULT_COLS, "grid columns")
+	difficulty = flag.String("difficulty", "any", "any, easy, medium, hard or expert")
+	alphabet   = flag.String("alphabet", "", "letters to draw sets from (default the first rows*cols letters of A-Z)")
+	minSize    = flag.Int("min-size", core.DefaultGeneratorOptions().MinSetSize, "smallest set size")
+	maxSize    = flag.Int("max-size", core.DefaultGeneratorOptions().MaxSetSize, "largest set size")
+	format     = flag.String("format", "json", "output format: json for JSON lines, text for printable grids")
+	noSolution = flag.Bool("no-solution", false, "leave the solutions out")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gridder-gen: ")
+	flag.Parse()
+
+	d, err := core.ParseDifficulty(*difficulty)
+	if err != nil {
+		log.Fatal(err)
+	}
+	options := core.GeneratorOptions{
+		Alphabet:   *alphabet,
+		MinSetSize: *minSize,
+		MaxSetSize: *maxSize,
+	}
+	if *rows < 1 || *cols < 1 {
+		log.Fatalf("invalid grid size %dx%d", *rows, *cols)
+	}
+	if err := options.Validate(*rows * *cols); err != nil {
+		log.Fatal(err)
+	}
+	if *seed > 1<<32-1 || *seed+int64(*count) > 1<<32 {
+		log.Fatal("seeds have to fit in 32 bits")
+	}
+	if *format != "json" && *format != "text" {
+		log.Fatalf("unknown format %q", *format)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	g := core.NewGame()
+	g.Options = options
+	g.Rows = *rows
+	g.Cols = *cols
+	g.Difficulty = d
+	for i := range *count {
+		s := rand.Uint32()
+		if *seed >= 0 {
+			s = uint32(*seed + int64(i))
+		}
+		if err := g.ResetSeeded(s); err != nil {
+			log.Fatalf("seed %d: %v", s, err)
+		}
+
+		r := Record{
+			Puzzle:     *core.NewPuzzle(g),
+			Seed:       s,
+			Difficulty: g.Rating.Difficulty().String(),
+		}
+		r.Code, _ = g.ShareCode()
+		if *noSolution {
+			r.Solution = nil
+		}
+
+		if *format == "text" {
+			if i > 0 {
+				fmt.Fprintln(out)
+			}
+			writeText(out, &r)
+			continue
+		}
+		data, err := json.Marshal(r)
+		if err != nil {
+			log.Fatal(err)
+		}
+		out.Write(data)
+		out.WriteString("\n")
+	}
+}
+
+// writeText prints the puzzle as an empty grid with the row targets to the
+// right and the column targets below, followed by the sets to place.
+func writeText(w io.Writer, r *Record) {
+	header := fmt.Sprintf("Seed %d, %s", r.Seed, r.Difficulty)
+	if r.Code != "" {
+		header += ", code " + r.Code
+	}
+	fmt.Fprintln(w, header)
+
+	width := 0
+	for _, s := range append(r.Sets, r.ColTargets...) {
+		width = max(width, utf8.RuneCountInString(s))
+	}
+	width += 2
+	line := "+" + strings.Repeat(strings.Repeat("-", width)+"+", r.Cols)
+
+	fmt.Fprintln(w, line)
+	for _, t := range r.RowTargets {
+		fmt.Fprintln(w, "|"+strings.Repeat(strings.Repeat(" ", width)+"|", r.Cols)+" "+t)
+		fmt.Fprintln(w, line)
+	}
+	targets := " "
+	for _, t := range r.ColTargets {
+		targets += center(t, width) + " "
+	}
+	fmt.Fprintln(w, strings.TrimRight(targets, " "))
+
+	fmt.Fprintln(w, "Sets: "+strings.Join(r.Sets, " "))
+	if len(r.Solution) > 0 {
+		fmt.Fprintln(w, "Solution:")
+		for row := range r.Rows {
+			fmt.Fprintln(w, "  "+strings.Join(r.Solution[row*r.Cols:(row+1)*r.Cols], " "))
+		}
+	}
+}
+
+func center(s string, width int) string {
+	pad := width - utf8.RuneCountInString(s)
+	return strings.Repeat(" ", pad/2) + s + strings.Repeat(" ", pad-pad/2)
+}
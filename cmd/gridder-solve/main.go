@@ -0,0 +1,136 @@
+// Command gridder-solve checks hand-made puzzles before they are published.
+// It reads puzzles in the game's JSON format, one after another, from the
+// files given or from stdin, and prints every solution it finds.
+//
+// It exits with status 1 if any puzzle has no solution or more than one, and
+// with status 2 if the input is malformed.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/prizelobby/union-gridder/core"
+	"github.com/prizelobby/union-gridder/solver"
+)
+
+var (
+	limit = flag.Int("limit", 10, "stop after finding this many solutions of a puzzle")
+	quiet = flag.Bool("quiet", false, "only print the number of solutions")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gridder-solve: ")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: gridder-solve [flags] [file ...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *limit < 2 {
+		log.Fatal("-limit has to be at least 2 to tell unique puzzles apart")
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	status := 0
+	n := 0
+	for _, name := range files {
+		in := io.Reader(os.Stdin)
+		if name != "-" {
+			f, err := os.Open(name)
+			if err != nil {
+				out.Flush()
+				log.Print(err)
+				os.Exit(2)
+			}
+			defer f.Close()
+			in = f
+		}
+
+		dec := json.NewDecoder(in)
+		for {
+			p := &core.Puzzle{}
+			err := dec.Decode(p)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			n += 1
+			if err == nil {
+				err = p.Validate()
+			}
+			if err != nil {
+				out.Flush()
+				log.Printf("%s: puzzle %d: %v", name, n, err)
+				os.Exit(2)
+			}
+			if !check(out, n, p) {
+				status = 1
+			}
+		}
+	}
+	if n == 0 {
+		out.Flush()
+		log.Print("no puzzles in the input")
+		os.Exit(2)
+	}
+	out.Flush()
+	os.Exit(status)
+}
+
+// check solves p and prints the result, returning whether it has exactly one
+// solution.
+func check(w io.Writer, n int, p *core.Puzzle) bool {
+	s := solver.NewSolver(p.Rows, p.Cols, p.Sets, p.Targets())
+	s.Limit = *limit
+	count := s.Run()
+
+	switch {
+	case count == 0:
+		fmt.Fprintf(w, "puzzle %d: no solution\n", n)
+	case count == 1:
+		rating := core.RateDifficulty(p.Rows, p.Cols, p.Sets, p.Targets(), s.Solutions[0])
+		fmt.Fprintf(w, "puzzle %d: unique solution, %s\n", n, rating.Difficulty())
+	case count == *limit:
+		fmt.Fprintf(w, "puzzle %d: at least %d solutions\n", n, count)
+	default:
+		fmt.Fprintf(w, "puzzle %d: %d solutions\n", n, count)
+	}
+	if !*quiet {
+		for i, solution := range s.Solutions {
+			if count > 1 {
+				fmt.Fprintf(w, "solution %d:\n", i+1)
+			}
+			writeGrid(w, p.Cols, solution)
+		}
+	}
+	return count == 1
+}
+
+// writeGrid prints a solution with the cells of each column lined up.
+func writeGrid(w io.Writer, cols int, solution []string) {
+	width := 0
+	for _, s := range solution {
+		width = max(width, utf8.RuneCountInString(s))
+	}
+	for row := 0; row*cols < len(solution); row++ {
+		cells := make([]string, cols)
+		for i, s := range solution[row*cols : (row+1)*cols] {
+			cells[i] = s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+		}
+		fmt.Fprintln(w, "  "+strings.TrimRight(strings.Join(cells, " "), " "))
+	}
+}
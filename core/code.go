@@ -0,0 +1,111 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CODE_VERSION is stored in every share code. Bump it whenever a change to the
+// generator makes a seed produce a different puzzle, so that old codes are
+// rejected instead of silently loading the wrong board.
+const CODE_VERSION = 1
+
+// Share codes are Crockford base32, which skips I, L, O and U so that codes
+// are easy to read out and type.
+const CODE_ALPHABET = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+const CODE_LENGTH = 9
+
+// Bit layout of a share code, from the top: version, rows-1, cols-1,
+// difficulty and the 32 bit seed.
+const (
+	codeSeedBits       = 32
+	codeDifficultyBits = 3
+	codeSizeBits       = 3
+	codeVersionBits    = 4
+)
+
+var ErrNotShareable = errors.New("only generated puzzles with the default options have a share code")
+
+func (o GeneratorOptions) isDefault() bool {
+	d := DefaultGeneratorOptions()
+	return o.Alphabet == d.Alphabet && o.MinSetSize == d.MinSetSize && o.MaxSetSize == d.MaxSetSize &&
+		(len(o.SizeWeights) == 0 || slices.Equal(o.SizeWeights, d.sizeWeights()))
+}
+
+// ShareCode returns a short code such as "1Q4-7ZK-M0C" that LoadCode turns
+// back into the same puzzle.
+func (g *Game) ShareCode() (string, error) {
+	if !g.generated || !g.Options.isDefault() {
+		return "", ErrNotShareable
+	}
+	if g.Rows < 1 || g.Rows > 1<<codeSizeBits || g.Cols < 1 || g.Cols > 1<<codeSizeBits {
+		return "", fmt.Errorf("a %dx%d grid is too big for a share code", g.Rows, g.Cols)
+	}
+
+	n := uint64(CODE_VERSION)
+	n = n<<codeSizeBits | uint64(g.Rows-1)
+	n = n<<codeSizeBits | uint64(g.Cols-1)
+	n = n<<codeDifficultyBits | uint64(g.Difficulty)
+	n = n<<codeSeedBits | uint64(g.Seed)
+
+	code := make([]byte, CODE_LENGTH)
+	for i := CODE_LENGTH - 1; i >= 0; i-- {
+		code[i] = CODE_ALPHABET[n%32]
+		n /= 32
+	}
+	return string(code[0:3]) + "-" + string(code[3:6]) + "-" + string(code[6:9]), nil
+}
+
+// LoadCode generates the puzzle described by a share code. Dashes, spaces and
+// case are ignored, and the letters O, I and L are read as 0, 1 and 1.
+func (g *Game) LoadCode(code string) error {
+	code = strings.ToUpper(code)
+	code = strings.NewReplacer("-", "", " ", "", "O", "0", "I", "1", "L", "1").Replace(code)
+	if len(code) != CODE_LENGTH {
+		return fmt.Errorf("a code has %d characters", CODE_LENGTH)
+	}
+
+	var n uint64
+	for _, c := range code {
+		d := strings.IndexRune(CODE_ALPHABET, c)
+		if d == -1 {
+			return fmt.Errorf("%q is not a valid code character", c)
+		}
+		n = n*32 + uint64(d)
+	}
+
+	seed := uint32(n)
+	n >>= codeSeedBits
+	difficulty := Difficulty(n & (1<<codeDifficultyBits - 1))
+	n >>= codeDifficultyBits
+	cols := int(n&(1<<codeSizeBits-1)) + 1
+	n >>= codeSizeBits
+	rows := int(n&(1<<codeSizeBits-1)) + 1
+	n >>= codeSizeBits
+	version := int(n)
+
+	if version != CODE_VERSION {
+		return fmt.Errorf("code is from generator version %d, this game uses version %d", version, CODE_VERSION)
+	}
+	if !slices.Contains(DIFFICULTIES, difficulty) {
+		return errors.New("code has an unknown difficulty")
+	}
+	options := DefaultGeneratorOptions()
+	if err := options.Validate(rows * cols); err != nil {
+		return err
+	}
+
+	// generate into a copy so that g is left as it was on failure
+	next := *g
+	next.Options = options
+	next.Rows = rows
+	next.Cols = cols
+	next.Difficulty = difficulty
+	if err := next.ResetSeeded(seed); err != nil {
+		return err
+	}
+	*g = next
+	return nil
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestShareCodeRoundTrip(t *testing.T) {
+	for rows := 1; rows <= 8; rows++ {
+		for cols := 1; cols <= 8; cols++ {
+			if DefaultGeneratorOptions().Validate(rows*cols) != nil {
+				continue
+			}
+			// every size once, cycling through the difficulties
+			difficulty := DIFFICULTIES[(rows*8+cols)%len(DIFFICULTIES)]
+			seed := uint32(rows*1000 + cols)
+
+			g := NewGame()
+			g.Rows, g.Cols, g.Difficulty = rows, cols, difficulty
+			err := g.ResetSeeded(seed)
+			if errors.Is(err, ErrDifficultyNotReached) {
+				// not every grid size reaches every difficulty
+				difficulty = ANY_DIFFICULTY
+				g.Difficulty = difficulty
+				err = g.ResetSeeded(seed)
+			}
+			if err != nil {
+				t.Fatalf("%dx%d %v: %v", rows, cols, difficulty, err)
+			}
+			code, err := g.ShareCode()
+			if err != nil {
+				t.Fatalf("%dx%d %v: %v", rows, cols, difficulty, err)
+			}
+
+			h := NewGame()
+			if err := h.LoadCode(strings.ToLower(code)); err != nil {
+				t.Fatalf("%s: %v", code, err)
+			}
+			if h.Rows != rows || h.Cols != cols || h.Difficulty != difficulty || h.Seed != seed {
+				t.Errorf("%s loaded as %dx%d %v seed %d, want %dx%d %v seed %d",
+					code, h.Rows, h.Cols, h.Difficulty, h.Seed, rows, cols, difficulty, seed)
+			}
+			if !slices.Equal(h.Sets, g.Sets) || !slices.Equal(h.Targets, g.Targets) || !slices.Equal(h.Solution, g.Solution) {
+				t.Errorf("%s loaded a different puzzle", code)
+			}
+			if again, _ := h.ShareCode(); again != code {
+				t.Errorf("%s loaded and shared as %s", code, again)
+			}
+		}
+	}
+}
+
+func TestShareCodeDifficulties(t *testing.T) {
+	for _, difficulty := range DIFFICULTIES {
+		g := NewGame()
+		g.Difficulty = difficulty
+		if err := g.ResetSeeded(42); err != nil {
+			t.Fatalf("%v: %v", difficulty, err)
+		}
+		code, err := g.ShareCode()
+		if err != nil {
+			t.Fatalf("%v: %v", difficulty, err)
+		}
+		h := NewGame()
+		if err := h.LoadCode(code); err != nil {
+			t.Fatalf("%s: %v", code, err)
+		}
+		if h.Difficulty != difficulty || !slices.Equal(h.Solution, g.Solution) {
+			t.Errorf("%s loaded %v %v, want %v %v", code, h.Difficulty, h.Solution, difficulty, g.Solution)
+		}
+	}
+}
+
+// TestShareCodeVersion1 pins a code to its puzzle. If it fails, the generator
+// changed what a seed produces and CODE_VERSION needs bumping.
+func TestShareCodeVersion1(t *testing.T) {
+	g := NewGame()
+	if err := g.LoadCode("2J0-000-005"); err != nil {
+		t.Fatal(err)
+	}
+	if g.Rows != 3 || g.Cols != 3 || g.Difficulty != ANY_DIFFICULTY || g.Seed != 5 {
+		t.Fatalf("loaded %dx%d %v seed %d", g.Rows, g.Cols, g.Difficulty, g.Seed)
+	}
+	sets := strings.Fields("BCI CDF CE DE DGH DH EG FHI FI")
+	targets := strings.Fields("BCDEGHI CDEFHI CDEFGHI DEGH CDEF BCFHI")
+	solution := strings.Fields("DGH DE BCI DH CE FI EG CDF FHI")
+	if !slices.Equal(g.Sets, sets) {
+		t.Errorf("sets %v, want %v", g.Sets, sets)
+	}
+	if !slices.Equal(g.Targets, targets) {
+		t.Errorf("targets %v, want %v", g.Targets, targets)
+	}
+	if !slices.Equal(g.Solution, solution) {
+		t.Errorf("solution %v, want %v", g.Solution, solution)
+	}
+}
+
+func TestLoadCodeRejects(t *testing.T) {
+	codes := []string{
+		"",
+		"2J0-000-00",  // too short
+		"2J0-000-00U", // U is not a code character
+		"4J0-000-005", // version 2
+		"2JW-000-005", // difficulty 7
+	}
+	for _, code := range codes {
+		if err := NewGame().LoadCode(code); err == nil {
+			t.Errorf("%q was accepted", code)
+		}
+	}
+}
+
+func TestLoadCodeFailureKeepsGame(t *testing.T) {
+	// a valid code for a puzzle the generator can't make
+	unreachable := &Game{Options: DefaultGeneratorOptions(), Rows: 2, Cols: 2, Difficulty: EXPERT, generated: true}
+	code, err := unreachable.ShareCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGame()
+	if err := g.ResetSeeded(1); err != nil {
+		t.Fatal(err)
+	}
+	g.SetSlot(0, g.Solution[0])
+	if err := g.LoadCode(code); !errors.Is(err, ErrDifficultyNotReached) {
+		t.Fatalf("%s: got %v, want ErrDifficultyNotReached", code, err)
+	}
+	if g.Rows != 3 || g.Cols != 3 || g.Difficulty != ANY_DIFFICULTY || g.Seed != 1 {
+		t.Errorf("failed load changed the game to %dx%d %v seed %d", g.Rows, g.Cols, g.Difficulty, g.Seed)
+	}
+	if len(g.Slots) != 9 || g.Slots[0] != g.Solution[0] || len(g.Matches) != 6 {
+		t.Error("failed load changed the board")
+	}
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+)
+
+// The daily puzzle is the same for everyone, so its settings are fixed.
+const DAILY_ROWS = 3
+const DAILY_COLS = 3
+const DAILY_DIFFICULTY = MEDIUM
+
+const DATE_FORMAT = "2006-01-02"
+
+// DailyDate returns the UTC calendar date of t, which names the daily puzzle.
+func DailyDate(t time.Time) string {
+	return t.UTC().Format(DATE_FORMAT)
+}
+
+func DailySeed(date string) uint32 {
+	sum := sha256.Sum256([]byte("daily " + date))
+	return binary.BigEndian.Uint32(sum[:4])
+}
+
+// ResetDaily generates the daily puzzle for the UTC date of t. Like
+// ResetSeeded, it leaves the game as it was on failure.
+func (g *Game) ResetDaily(t time.Time) error {
+	next := *g
+	next.Options = DefaultGeneratorOptions()
+	next.Rows = DAILY_ROWS
+	next.Cols = DAILY_COLS
+	next.Difficulty = DAILY_DIFFICULTY
+	date := DailyDate(t)
+	if err := next.ResetSeeded(DailySeed(date)); err != nil {
+		return err
+	}
+	next.Daily = date
+	*g = next
+	return nil
+}
+
+// Advance adds dt to the time spent on the puzzle, until it is solved.
+func (g *Game) Advance(dt time.Duration) {
+	if !g.Solved {
+		g.Elapsed += dt
+	}
+}
+
+type DailyRecord struct {
+	Date string        `json:"date"`
+	Time time.Duration `json:"time"`
+}
+
+// DailyLog records which daily puzzles were completed and how fast.
+type DailyLog struct {
+	Records map[string]DailyRecord `json:"records"`
+}
+
+func NewDailyLog() *DailyLog {
+	return &DailyLog{
+		Records: make(map[string]DailyRecord),
+	}
+}
+
+// Complete records a solve of the daily puzzle for date, keeping the best time.
+func (l *DailyLog) Complete(date string, elapsed time.Duration) {
+	if l.Records == nil {
+		l.Records = make(map[string]DailyRecord)
+	}
+	if r, ok := l.Records[date]; ok && r.Time <= elapsed {
+		return
+	}
+	l.Records[date] = DailyRecord{Date: date, Time: elapsed}
+}
+
+func (l *DailyLog) Completed(date string) (DailyRecord, bool) {
+	r, ok := l.Records[date]
+	return r, ok
+}
@@ -0,0 +1,326 @@
+package core
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+	"strings"
+
+	"github.com/prizelobby/union-gridder/solver"
+	"github.com/prizelobby/union-gridder/util"
+)
+
+type Difficulty int
+
+const (
+	ANY_DIFFICULTY Difficulty = iota
+	EASY
+	MEDIUM
+	HARD
+	EXPERT
+)
+
+var DIFFICULTIES = []Difficulty{ANY_DIFFICULTY, EASY, MEDIUM, HARD, EXPERT}
+
+func (d Difficulty) String() string {
+	switch d {
+	case EASY:
+		return "Easy"
+	case MEDIUM:
+		return "Medium"
+	case HARD:
+		return "Hard"
+	case EXPERT:
+		return "Expert"
+	}
+	return "Any"
+}
+
+// ParseDifficulty reads a difficulty name as printed by String, ignoring case.
+func ParseDifficulty(name string) (Difficulty, error) {
+	for _, d := range DIFFICULTIES {
+		if strings.EqualFold(name, d.String()) {
+			return d, nil
+		}
+	}
+	return ANY_DIFFICULTY, fmt.Errorf("unknown difficulty %q", name)
+}
+
+// MAX_DIFFICULTY_ATTEMPTS is how many unique puzzles Reset will try to land in
+// the requested difficulty before giving up with ErrDifficultyNotReached. Small
+// grids can't be hard: 2x2 puzzles are never rated above Medium, and a single
+// row or column is always Easy.
+const MAX_DIFFICULTY_ATTEMPTS = 2000
+
+// Weights of each deduction when scoring a puzzle.
+const (
+	NAKED_SINGLE_SCORE  = 1
+	HIDDEN_SINGLE_SCORE = 2
+	LINE_SCORE          = 4
+	GUESS_SCORE         = 10
+	BRANCH_SCORE        = 5
+)
+
+type DifficultyReport struct {
+	// Steps is the number of deductions needed, not counting guesses.
+	Steps         int
+	NakedSingles  int
+	HiddenSingles int
+	// LineDeductions counts eliminations made from a target letter that only
+	// one set or one cell of a row or column can still provide.
+	LineDeductions int
+	// Guesses is how often the deductions ran dry and a cell had to be guessed,
+	// and Branches the number of wrong options those guesses had.
+	Guesses  int
+	Branches int
+	Score    int
+	Cells    int
+}
+
+// Difficulty buckets the report by its score per cell, so that bigger grids
+// aren't rated harder just for having more cells.
+func (r DifficultyReport) Difficulty() Difficulty {
+	perCell := float64(r.Score) / float64(max(r.Cells, 1))
+	switch {
+	case perCell < 1.5:
+		return EASY
+	case perCell < 2.5:
+		return MEDIUM
+	case perCell < 4:
+		return HARD
+	}
+	return EXPERT
+}
+
+// deduction is the state of a simulated player working through a puzzle.
+type deduction struct {
+	rows, cols int
+	sets       []util.LetterSet
+	targets    []util.LetterSet
+	solution   []int
+	// candidates holds a bitmask of the sets that may still go in each cell
+	candidates []uint64
+	placed     []int
+	report     DifficultyReport
+}
+
+// RateDifficulty estimates how hard a puzzle is for a person by repeatedly
+// applying the simplest deduction that makes progress, and guessing from
+// solution when none does. If solution is empty it is found with the solver.
+func RateDifficulty(rows, cols int, sets, targets, solution []string) DifficultyReport {
+	if len(solution) == 0 {
+		solutions := solver.NewSolver(rows, cols, sets, targets)
+		solutions.Limit = 1
+		if solutions.Run() == 0 {
+			return DifficultyReport{}
+		}
+		solution = solutions.Solutions[0]
+	}
+	alphabet, err := util.AlphabetOf(targets...)
+	if err != nil || len(sets) > 64 {
+		return DifficultyReport{}
+	}
+
+	d := &deduction{
+		rows:       rows,
+		cols:       cols,
+		sets:       make([]util.LetterSet, len(sets)),
+		targets:    make([]util.LetterSet, len(targets)),
+		solution:   make([]int, len(solution)),
+		candidates: make([]uint64, rows*cols),
+		placed:     make([]int, rows*cols),
+	}
+	for i, s := range sets {
+		d.sets[i] = alphabet.Set(s)
+	}
+	for i, t := range targets {
+		d.targets[i] = alphabet.Set(t)
+	}
+	if len(solution) != rows*cols {
+		return DifficultyReport{}
+	}
+	for i, s := range solution {
+		d.solution[i] = slices.Index(sets, s)
+		if d.solution[i] == -1 {
+			return DifficultyReport{}
+		}
+	}
+	for cell := range d.candidates {
+		d.placed[cell] = -1
+		allowed := d.targets[d.row(cell)] & d.targets[d.col(cell)]
+		for k, s := range d.sets {
+			if s.IsSubsetOf(allowed) && alphabet.Covers(sets[k]) {
+				d.candidates[cell] |= 1 << k
+			}
+		}
+	}
+
+	d.report.Cells = rows * cols
+	d.run()
+	return d.report
+}
+
+func (g *Game) RateDifficulty() DifficultyReport {
+	return RateDifficulty(g.Rows, g.Cols, g.Sets, g.Targets, g.Solution)
+}
+
+func (d *deduction) row(cell int) int {
+	return cell / d.cols
+}
+
+func (d *deduction) col(cell int) int {
+	return d.rows + cell%d.cols
+}
+
+func (d *deduction) lineCells(line int) []int {
+	cells := []int{}
+	for cell := range d.candidates {
+		if d.row(cell) == line || d.col(cell) == line {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+func (d *deduction) run() {
+	for slices.Contains(d.placed, -1) {
+		switch {
+		case d.nakedSingle():
+			d.report.NakedSingles += 1
+			d.report.Score += NAKED_SINGLE_SCORE
+		case d.hiddenSingle():
+			d.report.HiddenSingles += 1
+			d.report.Score += HIDDEN_SINGLE_SCORE
+		case d.lineDeduction():
+			d.report.LineDeductions += 1
+			d.report.Score += LINE_SCORE
+		default:
+			d.guess()
+			continue
+		}
+		d.report.Steps += 1
+	}
+}
+
+func (d *deduction) place(cell, set int) {
+	d.placed[cell] = set
+	for other := range d.candidates {
+		d.candidates[other] &^= 1 << set
+	}
+	d.candidates[cell] = 1 << set
+}
+
+// nakedSingle places a set in a cell that has only one candidate left.
+func (d *deduction) nakedSingle() bool {
+	for cell, c := range d.candidates {
+		if d.placed[cell] == -1 && bits.OnesCount64(c) == 1 {
+			d.place(cell, bits.TrailingZeros64(c))
+			return true
+		}
+	}
+	return false
+}
+
+// hiddenSingle places a set that only fits in one cell.
+func (d *deduction) hiddenSingle() bool {
+	for k := range d.sets {
+		if slices.Contains(d.placed, k) {
+			continue
+		}
+		only := -1
+		count := 0
+		for cell, c := range d.candidates {
+			if d.placed[cell] == -1 && c&(1<<k) != 0 {
+				only = cell
+				count += 1
+			}
+		}
+		if count == 1 {
+			d.place(only, k)
+			return true
+		}
+	}
+	return false
+}
+
+// lineDeduction looks for a target letter that is still missing from a row or
+// column. If only one set can provide it, that set is ruled out everywhere
+// outside the line; if only one cell can, sets without the letter are ruled
+// out of that cell.
+func (d *deduction) lineDeduction() bool {
+	for line := range d.targets {
+		cells := d.lineCells(line)
+		var covered util.LetterSet
+		for _, cell := range cells {
+			if d.placed[cell] != -1 {
+				covered |= d.sets[d.placed[cell]]
+			}
+		}
+		missing := d.targets[line].Difference(covered)
+		for bit := 0; missing != 0; bit++ {
+			letter := util.LetterSet(1) << bit
+			if missing&letter == 0 {
+				continue
+			}
+			missing &^= letter
+
+			var providers uint64
+			providerCells := []int{}
+			for _, cell := range cells {
+				if d.placed[cell] != -1 {
+					continue
+				}
+				found := false
+				for k, s := range d.sets {
+					if d.candidates[cell]&(1<<k) != 0 && s&letter != 0 {
+						providers |= 1 << k
+						found = true
+					}
+				}
+				if found {
+					providerCells = append(providerCells, cell)
+				}
+			}
+
+			progress := false
+			if bits.OnesCount64(providers) == 1 {
+				for cell := range d.candidates {
+					if d.placed[cell] == -1 && !slices.Contains(cells, cell) && d.candidates[cell]&providers != 0 {
+						d.candidates[cell] &^= providers
+						progress = true
+					}
+				}
+			}
+			if len(providerCells) == 1 {
+				cell := providerCells[0]
+				for k, s := range d.sets {
+					if d.candidates[cell]&(1<<k) != 0 && s&letter == 0 {
+						d.candidates[cell] &^= 1 << k
+						progress = true
+					}
+				}
+			}
+			if progress {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// guess fills the unplaced cell with the fewest candidates from the solution.
+func (d *deduction) guess() {
+	best := -1
+	for cell, c := range d.candidates {
+		if d.placed[cell] != -1 {
+			continue
+		}
+		if best == -1 || bits.OnesCount64(c) < bits.OnesCount64(d.candidates[best]) {
+			best = cell
+		}
+	}
+	d.report.Guesses += 1
+	d.report.Branches += max(bits.OnesCount64(d.candidates[best])-1, 0)
+	d.report.Score += GUESS_SCORE + BRANCH_SCORE*max(bits.OnesCount64(d.candidates[best])-1, 0)
+	d.place(best, d.solution[best])
+}
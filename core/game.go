@@ -2,72 +2,169 @@ package core
 
 import (
 	"crypto/sha256"
+	"errors"
 	"math/rand/v2"
+	"strconv"
 	"strings"
 	"time"
 
 	"slices"
 
+	"github.com/prizelobby/union-gridder/solver"
 	"github.com/prizelobby/union-gridder/util"
 )
 
-const NUM_SETS = 9
+const DEFAULT_ROWS = 3
+const DEFAULT_COLS = 3
+
+const LETTERS = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 
 type Game struct {
-	Rand     *rand.Rand
-	Sets     []string
-	Targets  [6]string
-	Matches  [6][]bool
-	Slots    [9]string
-	Extras   [9][]bool
+	Rand    *rand.Rand
+	Options GeneratorOptions
+	// Difficulty is the band Reset aims for, ANY_DIFFICULTY accepts any puzzle.
+	Difficulty Difficulty
+	Rows       int
+	Cols       int
+	Sets       []string
+	// Targets holds the row targets followed by the column targets.
+	Targets  []string
+	Matches  [][]bool
+	Slots    []string
+	Extras   [][]bool
 	Solution []string
 	Solved   bool
+	Rating   DifficultyReport
+	// Seed is the seed the puzzle was generated from, see ResetSeeded.
+	Seed uint32
+	// Daily is the date of the daily puzzle being played, or empty.
+	Daily   string
+	Elapsed time.Duration
+	// History holds the moves made on the board, most recent last.
+	History   []Move
+	HintsUsed int
+	// Moves counts every change to the board, including undos and redos.
+	Moves int
+
+	generated bool
+	alphabet  util.Alphabet
+	future    []Move
+	lastHint  Hint
 }
 
-func (g *Game) Reset() {
-	g.Solved = false
-	var ALPHABET = "ABCDEFGHI"
-	var letters = []byte(ALPHABET)
+func (g *Game) NumSets() int {
+	return g.Rows * g.Cols
+}
+
+// RowTarget and ColTarget return the index into Targets for the row or column
+// that the slot at index belongs to.
+func (g *Game) RowTarget(index int) int {
+	return index / g.Cols
+}
+
+func (g *Game) ColTarget(index int) int {
+	return g.Rows + index%g.Cols
+}
+
+// targetAlphabet returns the alphabet of every letter used by the targets.
+func (g *Game) targetAlphabet() util.Alphabet {
+	if g.alphabet.Len() == 0 {
+		g.alphabet, _ = util.AlphabetOf(g.Targets...)
+	}
+	return g.alphabet
+}
+
+// lineUnions returns the union of every row followed by every column.
+func (g *Game) lineUnions(slots []util.LetterSet) []util.LetterSet {
+	unions := make([]util.LetterSet, g.Rows+g.Cols)
+	for i, s := range slots {
+		unions[g.RowTarget(i)] |= s
+		unions[g.ColTarget(i)] |= s
+	}
+	return unions
+}
+
+// MAX_GENERATION_ATTEMPTS is how many set lists ResetSeeded draws before
+// giving up. Some options, such as large sets from a small alphabet, can't
+// give a puzzle with a unique solution at all.
+const MAX_GENERATION_ATTEMPTS = 5000
+
+// MAX_SOLVER_NODES bounds the uniqueness check of a single candidate, which
+// counts as not unique when it runs out. MAX_GENERATION_NODES bounds the
+// checks of a whole ResetSeeded call, about two seconds of work. The default
+// options stay far below both, even for expert puzzles.
+const MAX_SOLVER_NODES = 20_000
+const MAX_GENERATION_NODES = 500_000
+
+var ErrNoUniquePuzzle = errors.New("no uniquely solvable puzzle found for these options")
+var ErrDifficultyNotReached = errors.New("no puzzle of the requested difficulty found for this grid")
+
+// Reset generates a new puzzle from a seed drawn from g.Rand.
+func (g *Game) Reset() error {
+	return g.ResetSeeded(g.Rand.Uint32())
+}
+
+// ResetSeeded generates the puzzle for seed. The same seed, grid size,
+// difficulty and options always give the same puzzle. The game is left as it
+// was if the options are invalid or no puzzle can be found.
+func (g *Game) ResetSeeded(seed uint32) error {
+	if err := g.Options.Validate(g.NumSets()); err != nil {
+		return err
+	}
+	r := seededRand(strconv.FormatUint(uint64(seed), 10))
+	alphabet, _ := g.Options.alphabet(g.NumSets())
+	letters := make([]int, alphabet.Len())
+	for i := range letters {
+		letters[i] = i
+	}
+	weights := g.Options.sizeWeights()
 
 	var found = false
+	var attempts = 0
+	var nodes = 0
 
-	for !found {
-		var sets = []string{}
-		for len(sets) < NUM_SETS {
-			var setSize = g.Rand.IntN(2) + 2
-			set := make([]byte, 0, setSize)
+	for draws := 0; !found; draws++ {
+		if draws == MAX_GENERATION_ATTEMPTS {
+			return ErrNoUniquePuzzle
+		}
+		var sets = []util.LetterSet{}
+		for len(sets) < g.NumSets() {
+			var setSize = g.Options.MinSetSize + util.WeightedIndex(weights, r)
+			var set util.LetterSet
 			for j := 0; j < setSize; j++ {
-				set = append(set, util.Choice(letters, func(c byte) bool { return !slices.Contains(set, c) }, g.Rand))
+				set |= 1 << util.Choice(letters, func(i int) bool { return set&(1<<i) == 0 }, r)
 			}
-			slices.Sort(set)
-			if !slices.Contains(sets, string(set)) {
-				sets = append(sets, string(set))
+			if !slices.Contains(sets, set) {
+				sets = append(sets, set)
 			}
 		}
-		slices.Sort(sets)
+		slices.SortFunc(sets, func(a, b util.LetterSet) int {
+			return strings.Compare(alphabet.Format(a), alphabet.Format(b))
+		})
 
-		permutations := make([][]string, 0, 72)
-		permutations = append(permutations, sets)
-		for i := 0; i < 9; i++ {
-			for j := i + 1; j < 9; j++ {
-				sets2 := slices.Clone(sets)
+		// the tray lists the sets in sorted order, so try arrangements around a
+		// shuffled one rather than around the tray order
+		base := slices.Clone(sets)
+		r.Shuffle(len(base), func(i, j int) {
+			base[i], base[j] = base[j], base[i]
+		})
+		permutations := make([][]util.LetterSet, 0, len(base)*(len(base)-1)/2+1)
+		permutations = append(permutations, base)
+		for i := 0; i < len(base); i++ {
+			for j := i + 1; j < len(base); j++ {
+				sets2 := slices.Clone(base)
 				sets2[i], sets2[j] = sets2[j], sets2[i]
 				permutations = append(permutations, sets2)
 			}
 		}
 		seen := make(map[string][]int)
+		keys := make([]string, len(permutations))
 		for i, p := range permutations {
-			unions := []string{util.StringsUnion(p[0], p[1], p[2]),
-				util.StringsUnion(p[3], p[4], p[5]),
-				util.StringsUnion(p[6], p[7], p[8]),
-				util.StringsUnion(p[0], p[3], p[6]),
-				util.StringsUnion(p[1], p[4], p[7]),
-				util.StringsUnion(p[2], p[5], p[8]),
-			}
-			var u_string = ""
-			for _, u := range unions {
-				u_string += string(u) + ","
+			u_string := ""
+			for _, u := range g.lineUnions(p) {
+				u_string += strconv.FormatUint(uint64(u), 36) + ","
 			}
+			keys[i] = u_string
 			if s, ok := seen[u_string]; ok {
 				seen[u_string] = []int{s[0], s[1] + 1}
 			} else {
@@ -75,84 +172,131 @@ func (g *Game) Reset() {
 			}
 		}
 
-		for u_string, s := range seen {
-			if s[1] == 1 {
-				//fmt.Printf("Unique union %s found in permutation %d\n", u_string, s[0])
+		// walk the permutations in a seeded order rather than the map order so
+		// that a seed always picks the same one
+		for _, k := range r.Perm(len(keys)) {
+			if s := seen[keys[k]]; s[1] == 1 {
 				perm := permutations[s[0]]
-				permStr := ""
-				for _, p := range perm {
-					permStr += string(p) + " "
-				}
-				//fmt.Printf("Permutation: %s\n", permStr)
-				g.Solution = perm
-				p := slices.Clone(perm)
-				slices.Sort(p)
-				g.Sets = p
-
-				unions := strings.Split(u_string, ",")
-				targets := [6]string{}
-				matches := [6][]bool{}
+				unions := g.lineUnions(perm)
+				targets := make([]string, len(unions))
+				matches := make([][]bool, len(unions))
 				for i, u := range unions {
-					if u == "" {
-						continue
+					targets[i] = alphabet.Format(u)
+					matches[i] = make([]bool, u.Len())
+				}
+
+				setNames := make([]string, len(sets))
+				solution := make([]string, len(perm))
+				for i := range sets {
+					setNames[i] = alphabet.Format(sets[i])
+					solution[i] = alphabet.Format(perm[i])
+				}
+
+				if nodes >= MAX_GENERATION_NODES {
+					return ErrNoUniquePuzzle
+				}
+				// the swaps above only rule out nearby arrangements, so make
+				// sure no other arrangement of the sets hits the same targets
+				s := solver.NewSolver(g.Rows, g.Cols, setNames, targets)
+				s.Limit = 2
+				s.MaxNodes = MAX_SOLVER_NODES
+				unique := s.Run() == 1 && !s.Exceeded
+				nodes += s.Nodes
+				if !unique {
+					continue
+				}
+
+				rating := RateDifficulty(g.Rows, g.Cols, setNames, targets, solution)
+				attempts += 1
+				if g.Difficulty != ANY_DIFFICULTY && rating.Difficulty() != g.Difficulty {
+					if attempts == MAX_DIFFICULTY_ATTEMPTS {
+						return ErrDifficultyNotReached
 					}
-					targets[i] = u
-					matches[i] = make([]bool, len(u))
+					continue
 				}
+
+				g.Solution = solution
+				g.Sets = setNames
 				g.Targets = targets
 				g.Matches = matches
+				g.Rating = rating
 				found = true
 				break
 			}
 		}
 	}
 
-	g.Extras = [9][]bool{}
-	g.Slots = [9]string{}
+	g.Seed = seed
+	g.generated = true
+	g.Daily = ""
+	g.Elapsed = 0
+	g.clearHistory()
+	g.HintsUsed = 0
+	g.Moves = 0
+	g.lastHint = Hint{}
+	g.Solved = false
+	g.alphabet = util.Alphabet{}
+	g.Extras = make([][]bool, g.NumSets())
+	g.Slots = make([]string, g.NumSets())
+	return nil
 }
 
+// SetSlot puts set into the cell at index, or empties it for "", and updates
+// Matches, Extras and Solved. Validate explains why the board is or isn't
+// solved.
 func (g *Game) SetSlot(index int, set string) {
 	g.Slots[index] = set
-	t := []string{util.StringsUnion(g.Slots[0], g.Slots[1], g.Slots[2]),
-		util.StringsUnion(g.Slots[3], g.Slots[4], g.Slots[5]),
-		util.StringsUnion(g.Slots[6], g.Slots[7], g.Slots[8]),
-		util.StringsUnion(g.Slots[0], g.Slots[3], g.Slots[6]),
-		util.StringsUnion(g.Slots[1], g.Slots[4], g.Slots[7]),
-		util.StringsUnion(g.Slots[2], g.Slots[5], g.Slots[8])}
-
+	alphabet := g.targetAlphabet()
+	// Letters outside the targets can't be in a LetterSet, so a board holding
+	// any is never solved.
 	solved := true
-	g.Extras[index] = make([]bool, len(set))
-	for i, b := range []byte(set) {
-		g.Extras[index][i] = !slices.Contains([]byte(g.Targets[index/3]), b) || !slices.Contains([]byte(g.Targets[index%3+3]), b)
+	slots := make([]util.LetterSet, len(g.Slots))
+	for i, s := range g.Slots {
+		slots[i] = alphabet.Set(s)
+		if s == "" || !alphabet.Covers(s) {
+			solved = false
+		}
 	}
+	t := g.lineUnions(slots)
 
-	for j := range 6 {
-		if t[j] != g.Targets[j] {
-			solved = false
+	rowTarget := alphabet.Set(g.Targets[g.RowTarget(index)])
+	colTarget := alphabet.Set(g.Targets[g.ColTarget(index)])
+	g.Extras[index] = make([]bool, 0, len(set))
+	for _, r := range set {
+		g.Extras[index] = append(g.Extras[index], !alphabet.Has(rowTarget, r) || !alphabet.Has(colTarget, r))
+	}
+
+	for j := range t {
+		for i, r := range []rune(g.Targets[j]) {
+			g.Matches[j][i] = alphabet.Has(t[j], r)
 		}
-		for i, b := range g.Targets[j] {
-			if strings.Contains(t[j], string(b)) {
-				g.Matches[j][i] = true
-			} else {
-				g.Matches[j][i] = false
-			}
+		if t[j] != alphabet.Set(g.Targets[j]) {
+			solved = false
 		}
 	}
 
 	g.Solved = solved
 }
 
-func NewGame() *Game {
-	seed := time.Now().String()
+func seededRand(seed string) *rand.Rand {
 	sum := sha256.Sum256([]byte(seed))
+	return rand.New(rand.NewChaCha8(sum))
+}
+
+func NewGame() *Game {
 	return &Game{
-		Rand: rand.New(rand.NewChaCha8(sum)),
+		Rand:    seededRand(time.Now().String()),
+		Options: DefaultGeneratorOptions(),
+		Rows:    DEFAULT_ROWS,
+		Cols:    DEFAULT_COLS,
 	}
 }
 
 func NewGameSeeded(seed string) *Game {
-	sum := sha256.Sum256([]byte(seed))
 	return &Game{
-		Rand: rand.New(rand.NewChaCha8(sum)),
+		Rand:    seededRand(seed),
+		Options: DefaultGeneratorOptions(),
+		Rows:    DEFAULT_ROWS,
+		Cols:    DEFAULT_COLS,
 	}
 }
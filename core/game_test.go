@@ -0,0 +1,55 @@
+package core
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestResetSeededGivesUp(t *testing.T) {
+	g := NewGame()
+	if err := g.ResetSeeded(1); err != nil {
+		t.Fatal(err)
+	}
+	sets := slices.Clone(g.Sets)
+
+	// every set fits almost everywhere, so uniqueness checks are expensive
+	g.Rows, g.Cols = 4, 4
+	g.Options = GeneratorOptions{Alphabet: "ABCDE", MinSetSize: 1, MaxSetSize: 4}
+	start := time.Now()
+	err := g.ResetSeeded(0)
+	if !errors.Is(err, ErrNoUniquePuzzle) {
+		t.Fatalf("got %v, want ErrNoUniquePuzzle", err)
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("gave up after %v", d)
+	}
+	if !slices.Equal(g.Sets, sets) || g.Seed != 1 {
+		t.Error("failed reset changed the puzzle")
+	}
+}
+
+func TestResetSeededMissesDifficulty(t *testing.T) {
+	g := NewGame()
+	if err := g.ResetSeeded(1); err != nil {
+		t.Fatal(err)
+	}
+	sets := slices.Clone(g.Sets)
+
+	g.Rows, g.Cols, g.Difficulty = 2, 2, EXPERT
+	if err := g.ResetSeeded(0); !errors.Is(err, ErrDifficultyNotReached) {
+		t.Fatalf("got %v, want ErrDifficultyNotReached", err)
+	}
+	if !slices.Equal(g.Sets, sets) || g.Seed != 1 {
+		t.Error("failed reset changed the puzzle")
+	}
+
+	g.Difficulty = MEDIUM
+	if err := g.ResetSeeded(0); err != nil {
+		t.Fatal(err)
+	}
+	if d := g.Rating.Difficulty(); d != MEDIUM {
+		t.Errorf("asked for Medium, got %v", d)
+	}
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/prizelobby/union-gridder/solver"
+)
+
+type HintLevel int
+
+const (
+	// WRONG_CELL points out a cell holding the wrong set.
+	WRONG_CELL HintLevel = iota + 1
+	// SET_ROW tells which row a set belongs in.
+	SET_ROW
+	// PLACEMENT gives the exact cell for a set.
+	PLACEMENT
+)
+
+type Hint struct {
+	Level HintLevel
+	// Index is the cell the hint is about, Row and Col its position.
+	Index int
+	Row   int
+	Col   int
+	Set   string
+}
+
+func (h Hint) String() string {
+	switch h.Level {
+	case WRONG_CELL:
+		return fmt.Sprintf("%s in row %d, column %d is wrong", h.Set, h.Row+1, h.Col+1)
+	case SET_ROW:
+		return fmt.Sprintf("%s belongs in row %d", h.Set, h.Row+1)
+	}
+	return fmt.Sprintf("%s goes in row %d, column %d", h.Set, h.Row+1, h.Col+1)
+}
+
+// Hint returns a hint for the current board and counts it in HintsUsed. Asking
+// again about the same set gives away more each time: first the wrong cell,
+// then the row, then the exact placement. It returns false when there is
+// nothing to hint, because the board is solved or the puzzle has no solution.
+func (g *Game) Hint() (Hint, bool) {
+	solution := g.hintSolution()
+	if solution == nil || g.Solved {
+		return Hint{}, false
+	}
+
+	h := Hint{Level: SET_ROW, Index: -1}
+	for i, s := range g.Slots {
+		if s != "" && s != solution[i] {
+			h = Hint{Level: WRONG_CELL, Index: i, Set: s}
+			break
+		}
+	}
+	if h.Index == -1 {
+		for i, s := range solution {
+			if g.Slots[i] != s {
+				h.Set = s
+				break
+			}
+		}
+	}
+	if h.Set == "" {
+		return Hint{}, false
+	}
+
+	// escalate when the player asks about the same set again
+	if h.Set == g.lastHint.Set && g.lastHint.Level >= h.Level {
+		h.Level = min(g.lastHint.Level+1, PLACEMENT)
+	}
+	if h.Level != WRONG_CELL {
+		h.Index = slices.Index(solution, h.Set)
+	}
+	h.Row = h.Index / g.Cols
+	h.Col = h.Index % g.Cols
+
+	g.lastHint = h
+	g.HintsUsed += 1
+	return h, true
+}
+
+// hintSolution returns the solution the hints steer towards. A solution that
+// keeps the sets already placed is preferred, so that boards heading to a
+// valid alternate solution aren't called wrong.
+func (g *Game) hintSolution() []string {
+	s := solver.NewSolver(g.Rows, g.Cols, g.Sets, g.Targets)
+	s.Fixed = g.Slots
+	s.Limit = 1
+	if s.Run() > 0 {
+		return s.Solutions[0]
+	}
+	if len(g.Solution) > 0 {
+		return g.Solution
+	}
+	s.Fixed = nil
+	if s.Run() > 0 {
+		return s.Solutions[0]
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package core
+
+import "slices"
+
+type MoveKind int
+
+const (
+	// PLACE puts a set into a cell, from the tray or from another cell. A set
+	// already in the cell goes back to the tray.
+	PLACE MoveKind = iota
+	// REMOVE sends the set in a cell back to the tray.
+	REMOVE
+	// SWAP exchanges the sets of two cells.
+	SWAP
+)
+
+type SlotChange struct {
+	Index  int
+	Before string
+	After  string
+}
+
+type Move struct {
+	Kind    MoveKind
+	Changes []SlotChange
+}
+
+// Place moves set into the cell at index and records it in the history. If set
+// is already in another cell, that cell is emptied as part of the same move.
+func (g *Game) Place(index int, set string) {
+	if g.Slots[index] == set {
+		return
+	}
+	m := Move{Kind: PLACE}
+	if from := slices.Index(g.Slots, set); from != -1 {
+		m.Changes = append(m.Changes, SlotChange{Index: from, Before: set, After: ""})
+	}
+	m.Changes = append(m.Changes, SlotChange{Index: index, Before: g.Slots[index], After: set})
+	g.do(m)
+}
+
+// Remove empties the cell at index and records it in the history.
+func (g *Game) Remove(index int) {
+	if g.Slots[index] == "" {
+		return
+	}
+	g.do(Move{Kind: REMOVE, Changes: []SlotChange{{Index: index, Before: g.Slots[index], After: ""}}})
+}
+
+// Swap exchanges the sets in two cells and records it in the history.
+func (g *Game) Swap(a, b int) {
+	if a == b || g.Slots[a] == g.Slots[b] {
+		return
+	}
+	g.do(Move{Kind: SWAP, Changes: []SlotChange{
+		{Index: a, Before: g.Slots[a], After: g.Slots[b]},
+		{Index: b, Before: g.Slots[b], After: g.Slots[a]},
+	}})
+}
+
+func (g *Game) do(m Move) {
+	g.countMove()
+	for _, c := range m.Changes {
+		g.SetSlot(c.Index, c.After)
+	}
+	g.History = append(g.History, m)
+	g.future = g.future[:0]
+}
+
+func (g *Game) CanUndo() bool {
+	return len(g.History) > 0
+}
+
+func (g *Game) CanRedo() bool {
+	return len(g.future) > 0
+}
+
+// Undo reverts the last move, returning false if there was nothing to undo.
+func (g *Game) Undo() bool {
+	if !g.CanUndo() {
+		return false
+	}
+	g.countMove()
+	m := g.History[len(g.History)-1]
+	g.History = g.History[:len(g.History)-1]
+	for i := len(m.Changes) - 1; i >= 0; i-- {
+		g.SetSlot(m.Changes[i].Index, m.Changes[i].Before)
+	}
+	g.future = append(g.future, m)
+	return true
+}
+
+// Redo plays the last undone move again, returning false if there was none.
+func (g *Game) Redo() bool {
+	if !g.CanRedo() {
+		return false
+	}
+	g.countMove()
+	m := g.future[len(g.future)-1]
+	g.future = g.future[:len(g.future)-1]
+	for _, c := range m.Changes {
+		g.SetSlot(c.Index, c.After)
+	}
+	g.History = append(g.History, m)
+	return true
+}
+
+func (g *Game) clearHistory() {
+	g.History = nil
+	g.future = nil
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prizelobby/union-gridder/util"
+)
+
+type GeneratorOptions struct {
+	// Alphabet is the pool of letters that sets are drawn from. Any runes work,
+	// so digits or symbols are fine. When empty, the first Rows*Cols letters of
+	// LETTERS are used.
+	Alphabet   string `json:"alphabet,omitempty"`
+	MinSetSize int    `json:"min_set_size"`
+	MaxSetSize int    `json:"max_set_size"`
+	// SizeWeights holds the relative weight of each set size from MinSetSize
+	// to MaxSetSize. When empty, every size is equally likely.
+	SizeWeights []int `json:"size_weights,omitempty"`
+}
+
+func DefaultGeneratorOptions() GeneratorOptions {
+	return GeneratorOptions{
+		MinSetSize: 2,
+		MaxSetSize: 3,
+	}
+}
+
+// alphabet returns the letters used for a grid of numSets cells.
+func (o GeneratorOptions) alphabet(numSets int) (util.Alphabet, error) {
+	if o.Alphabet == "" {
+		if numSets > len(LETTERS) {
+			return util.Alphabet{}, fmt.Errorf("no default alphabet for %d sets", numSets)
+		}
+		return util.NewAlphabet(LETTERS[:numSets])
+	}
+	return util.NewAlphabet(o.Alphabet)
+}
+
+func (o GeneratorOptions) sizeWeights() []int {
+	if len(o.SizeWeights) > 0 {
+		return o.SizeWeights
+	}
+	weights := make([]int, o.MaxSetSize-o.MinSetSize+1)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return weights
+}
+
+// Validate reports whether a puzzle with numSets distinct sets can be generated
+// with these options.
+func (o GeneratorOptions) Validate(numSets int) error {
+	alphabet, err := o.alphabet(numSets)
+	if err != nil {
+		return err
+	}
+	if o.MinSetSize < 1 || o.MaxSetSize < o.MinSetSize {
+		return fmt.Errorf("invalid set sizes %d to %d", o.MinSetSize, o.MaxSetSize)
+	}
+	if o.MaxSetSize > alphabet.Len() {
+		return fmt.Errorf("set size %d is bigger than the %d letter alphabet", o.MaxSetSize, alphabet.Len())
+	}
+	weights := o.sizeWeights()
+	if len(weights) != o.MaxSetSize-o.MinSetSize+1 {
+		return fmt.Errorf("expected %d size weights, got %d", o.MaxSetSize-o.MinSetSize+1, len(weights))
+	}
+
+	// there have to be enough distinct sets for every cell of the grid
+	possible := 0
+	for i, w := range weights {
+		if w < 0 {
+			return errors.New("size weights cannot be negative")
+		}
+		if w > 0 {
+			possible += binomial(alphabet.Len(), o.MinSetSize+i)
+		}
+	}
+	if possible == 0 {
+		return errors.New("size weights are all zero")
+	}
+	if possible < numSets {
+		return fmt.Errorf("only %d distinct sets can be made, %d are needed", possible, numSets)
+	}
+	return nil
+}
+
+func binomial(n, k int) int {
+	out := 1
+	for i := 1; i <= k; i++ {
+		out = out * (n - k + i) / i
+	}
+	return out
+}
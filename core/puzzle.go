@@ -0,0 +1,165 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/prizelobby/union-gridder/util"
+)
+
+// Puzzle is the saved form of a game. Solution and Slots are optional, so a
+// hand-authored puzzle only needs the grid size, sets and targets.
+type Puzzle struct {
+	Rows       int      `json:"rows"`
+	Cols       int      `json:"cols"`
+	Sets       []string `json:"sets"`
+	RowTargets []string `json:"row_targets"`
+	ColTargets []string `json:"col_targets"`
+	Solution   []string `json:"solution,omitempty"`
+	Slots      []string `json:"slots,omitempty"`
+}
+
+// NewPuzzle returns the puzzle the game is holding. Slots are left out when
+// the board is empty.
+func NewPuzzle(g *Game) *Puzzle {
+	p := &Puzzle{
+		Rows:       g.Rows,
+		Cols:       g.Cols,
+		Sets:       slices.Clone(g.Sets),
+		RowTargets: slices.Clone(g.Targets[:g.Rows]),
+		ColTargets: slices.Clone(g.Targets[g.Rows:]),
+		Solution:   slices.Clone(g.Solution),
+	}
+	if slices.ContainsFunc(g.Slots, func(s string) bool { return s != "" }) {
+		p.Slots = slices.Clone(g.Slots)
+	}
+	return p
+}
+
+func (p *Puzzle) Targets() []string {
+	return append(slices.Clone(p.RowTargets), p.ColTargets...)
+}
+
+// Validate checks that the puzzle is well formed. It does not check that the
+// puzzle can be solved.
+func (p *Puzzle) Validate() error {
+	if p.Rows <= 0 || p.Cols <= 0 {
+		return fmt.Errorf("invalid grid size %dx%d", p.Rows, p.Cols)
+	}
+	if len(p.Sets) != p.Rows*p.Cols {
+		return fmt.Errorf("a %dx%d grid needs %d sets, got %d", p.Rows, p.Cols, p.Rows*p.Cols, len(p.Sets))
+	}
+	if len(p.RowTargets) != p.Rows {
+		return fmt.Errorf("expected %d row targets, got %d", p.Rows, len(p.RowTargets))
+	}
+	if len(p.ColTargets) != p.Cols {
+		return fmt.Errorf("expected %d column targets, got %d", p.Cols, len(p.ColTargets))
+	}
+	if _, err := util.AlphabetOf(p.Targets()...); err != nil {
+		return err
+	}
+	for i, s := range p.Sets {
+		if s == "" {
+			return errors.New("sets cannot be empty")
+		}
+		if slices.Contains(p.Sets[:i], s) {
+			return fmt.Errorf("set %s appears twice", s)
+		}
+	}
+	for _, t := range p.Targets() {
+		if t == "" {
+			return errors.New("targets cannot be empty")
+		}
+	}
+
+	if len(p.Solution) > 0 {
+		if len(p.Solution) != len(p.Sets) {
+			return fmt.Errorf("solution has %d sets, expected %d", len(p.Solution), len(p.Sets))
+		}
+		for i, s := range p.Solution {
+			if !slices.Contains(p.Sets, s) || slices.Contains(p.Solution[:i], s) {
+				return fmt.Errorf("solution set %s does not match the set list", s)
+			}
+		}
+		g := &Game{Rows: p.Rows, Cols: p.Cols, Targets: p.Targets()}
+		alphabet := g.targetAlphabet()
+		slots := make([]util.LetterSet, len(p.Solution))
+		for i, s := range p.Solution {
+			slots[i] = alphabet.Set(s)
+		}
+		for i, u := range g.lineUnions(slots) {
+			if u != alphabet.Set(g.Targets[i]) {
+				return errors.New("solution does not match the targets")
+			}
+		}
+	}
+
+	if len(p.Slots) > 0 {
+		if len(p.Slots) != len(p.Sets) {
+			return fmt.Errorf("expected %d slots, got %d", len(p.Sets), len(p.Slots))
+		}
+		for i, s := range p.Slots {
+			if s == "" {
+				continue
+			}
+			if !slices.Contains(p.Sets, s) || slices.Contains(p.Slots[:i], s) {
+				return fmt.Errorf("slot set %s does not match the set list", s)
+			}
+		}
+	}
+	return nil
+}
+
+// Load replaces the game's puzzle and board with p.
+func (g *Game) Load(p *Puzzle) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	g.Rows = p.Rows
+	g.Cols = p.Cols
+	g.Sets = slices.Clone(p.Sets)
+	g.Targets = p.Targets()
+	g.Solution = slices.Clone(p.Solution)
+	g.Seed = 0
+	g.generated = false
+	g.Daily = ""
+	g.Elapsed = 0
+	g.clearHistory()
+	g.HintsUsed = 0
+	g.Moves = 0
+	g.lastHint = Hint{}
+	g.alphabet = util.Alphabet{}
+
+	g.Matches = make([][]bool, len(g.Targets))
+	for i, t := range g.Targets {
+		g.Matches[i] = make([]bool, len([]rune(t)))
+	}
+	g.Extras = make([][]bool, g.NumSets())
+	g.Slots = make([]string, g.NumSets())
+	g.Solved = false
+	for i, s := range p.Slots {
+		g.SetSlot(i, s)
+	}
+	g.Rating = g.RateDifficulty()
+	return nil
+}
+
+// Marshal encodes the game's puzzle, solution and board as JSON.
+func Marshal(g *Game) ([]byte, error) {
+	return json.Marshal(NewPuzzle(g))
+}
+
+// Unmarshal decodes a puzzle written by Marshal or by hand into a new game.
+func Unmarshal(data []byte) (*Game, error) {
+	p := &Puzzle{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	g := NewGame()
+	if err := g.Load(p); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	g := NewGameSeeded("round trip")
+	if err := g.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	g.Place(0, g.Solution[0])
+	g.Place(4, g.Solution[1])
+
+	data, err := Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Rows != g.Rows || h.Cols != g.Cols {
+		t.Errorf("got a %dx%d grid, want %dx%d", h.Rows, h.Cols, g.Rows, g.Cols)
+	}
+	if !slices.Equal(h.Sets, g.Sets) {
+		t.Errorf("sets %v, want %v", h.Sets, g.Sets)
+	}
+	if !slices.Equal(h.Targets, g.Targets) {
+		t.Errorf("targets %v, want %v", h.Targets, g.Targets)
+	}
+	if !slices.Equal(h.Solution, g.Solution) {
+		t.Errorf("solution %v, want %v", h.Solution, g.Solution)
+	}
+	if !slices.Equal(h.Slots, g.Slots) {
+		t.Errorf("slots %v, want %v", h.Slots, g.Slots)
+	}
+}
+
+func TestUnmarshalHandWritten(t *testing.T) {
+	data := `{
+		"rows": 2,
+		"cols": 2,
+		"sets": ["AB", "C", "BD", "E"],
+		"row_targets": ["ABC", "BDE"],
+		"col_targets": ["ABD", "CE"]
+	}`
+	g, err := Unmarshal([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(g.Targets, []string{"ABC", "BDE", "ABD", "CE"}) {
+		t.Errorf("targets %v", g.Targets)
+	}
+	if len(g.Solution) != 0 {
+		t.Errorf("solution %v, want none", g.Solution)
+	}
+	if !slices.Equal(g.Slots, make([]string, 4)) {
+		t.Errorf("slots %v, want an empty board", g.Slots)
+	}
+	if g.Solved {
+		t.Error("empty board is solved")
+	}
+}
+
+func TestPuzzleValidateRejects(t *testing.T) {
+	valid := func() *Puzzle {
+		return &Puzzle{
+			Rows:       2,
+			Cols:       2,
+			Sets:       []string{"AB", "C", "BD", "E"},
+			RowTargets: []string{"ABC", "BDE"},
+			ColTargets: []string{"ABD", "CE"},
+			Solution:   []string{"AB", "C", "BD", "E"},
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("valid puzzle rejected: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(p *Puzzle)
+		want   string
+	}{
+		{"too few row targets", func(p *Puzzle) { p.RowTargets = p.RowTargets[:1] }, "row targets"},
+		{"too many column targets", func(p *Puzzle) { p.ColTargets = append(p.ColTargets, "A") }, "column targets"},
+		{"duplicate set", func(p *Puzzle) { p.Sets[3] = "AB" }, "twice"},
+		{"solution off target", func(p *Puzzle) { p.Solution = []string{"C", "AB", "BD", "E"} }, "does not match the targets"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid()
+			tt.change(p)
+			err := p.Validate()
+			if err == nil {
+				t.Fatal("accepted")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SaveGame is a game in progress: the puzzle and board, plus what is needed
+// to carry on where the player left off. Undo history is not kept.
+type SaveGame struct {
+	Puzzle
+	Options    GeneratorOptions `json:"options"`
+	Difficulty Difficulty       `json:"difficulty"`
+	Seed       uint32           `json:"seed,omitempty"`
+	Generated  bool             `json:"generated,omitempty"`
+	Daily      string           `json:"daily,omitempty"`
+	Elapsed    time.Duration    `json:"elapsed"`
+	Moves      int              `json:"moves"`
+	HintsUsed  int              `json:"hints_used"`
+}
+
+func NewSaveGame(g *Game) *SaveGame {
+	return &SaveGame{
+		Puzzle:     *NewPuzzle(g),
+		Options:    g.Options,
+		Difficulty: g.Difficulty,
+		Seed:       g.Seed,
+		Generated:  g.generated,
+		Daily:      g.Daily,
+		Elapsed:    g.Elapsed,
+		Moves:      g.Moves,
+		HintsUsed:  g.HintsUsed,
+	}
+}
+
+// Restore replaces the game with a saved one.
+func (g *Game) Restore(s *SaveGame) error {
+	// the options only matter for share codes of generated puzzles
+	if s.Generated {
+		if err := s.Options.Validate(s.Rows * s.Cols); err != nil {
+			return err
+		}
+	}
+	if err := g.Load(&s.Puzzle); err != nil {
+		return err
+	}
+	g.Options = s.Options
+	g.Difficulty = s.Difficulty
+	g.Seed = s.Seed
+	g.generated = s.Generated
+	g.Daily = s.Daily
+	g.Elapsed = s.Elapsed
+	g.Moves = s.Moves
+	g.HintsUsed = s.HintsUsed
+	return nil
+}
+
+func MarshalSave(g *Game) ([]byte, error) {
+	return json.Marshal(NewSaveGame(g))
+}
+
+// UnmarshalSave restores a game written by MarshalSave into g.
+func UnmarshalSave(g *Game, data []byte) error {
+	s := &SaveGame{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return err
+	}
+	return g.Restore(s)
+}
@@ -0,0 +1,36 @@
+package core
+
+import "time"
+
+// A solved puzzle is worth CELL_POINTS per cell plus DIFFICULTY_POINTS per
+// point of its difficulty score. Points are taken off for time over par,
+// moves beyond one per cell and hints.
+const CELL_POINTS = 100
+const DIFFICULTY_POINTS = 25
+const PAR_TIME_PER_CELL = 10 * time.Second
+const TIME_PENALTY = 2
+const MOVE_PENALTY = 10
+const HINT_PENALTY = 150
+
+// countMove counts a change to the board in Moves. Nothing is counted once
+// the puzzle is solved, so the count stays as it was at the solve.
+func (g *Game) countMove() {
+	if !g.Solved {
+		g.Moves += 1
+	}
+}
+
+// Score is the score for the game so far. Time, moves and hints stop counting
+// when the puzzle is solved, so the score is frozen from then on.
+func (g *Game) Score() int {
+	cells := g.NumSets()
+	score := cells*CELL_POINTS + g.Rating.Score*DIFFICULTY_POINTS
+
+	par := time.Duration(cells) * PAR_TIME_PER_CELL
+	if g.Elapsed > par {
+		score -= int((g.Elapsed-par)/time.Second) * TIME_PENALTY
+	}
+	score -= max(g.Moves-cells, 0) * MOVE_PENALTY
+	score -= g.HintsUsed * HINT_PENALTY
+	return max(score, 0)
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"slices"
+	"time"
+)
+
+// Solve times are grouped into HISTOGRAM_BUCKETS buckets of HISTOGRAM_WIDTH,
+// the last one holding everything slower.
+const HISTOGRAM_BUCKETS = 8
+const HISTOGRAM_WIDTH = 30 * time.Second
+
+// GameRecord is a finished game, either solved or abandoned for another one.
+type GameRecord struct {
+	Finished time.Time `json:"finished"`
+	// Code is the share code of the puzzle, when it has one.
+	Code       string        `json:"code,omitempty"`
+	Seed       uint32        `json:"seed,omitempty"`
+	Rows       int           `json:"rows"`
+	Cols       int           `json:"cols"`
+	Difficulty Difficulty    `json:"difficulty"`
+	Time       time.Duration `json:"time"`
+	Moves      int           `json:"moves"`
+	Hints      int           `json:"hints"`
+	Score      int           `json:"score"`
+	// Daily is the date of the daily puzzle, or empty.
+	Daily  string `json:"daily,omitempty"`
+	Solved bool   `json:"solved"`
+}
+
+func NewGameRecord(g *Game, finished time.Time) GameRecord {
+	code, _ := g.ShareCode()
+	r := GameRecord{
+		Finished:   finished,
+		Code:       code,
+		Seed:       g.Seed,
+		Rows:       g.Rows,
+		Cols:       g.Cols,
+		Difficulty: g.Rating.Difficulty(),
+		Time:       g.Elapsed,
+		Moves:      g.Moves,
+		Hints:      g.HintsUsed,
+		Daily:      g.Daily,
+		Solved:     g.Solved,
+	}
+	if g.Solved {
+		r.Score = g.Score()
+	}
+	return r
+}
+
+// Stats holds every finished game, oldest first.
+type Stats struct {
+	Games []GameRecord `json:"games"`
+}
+
+func NewStats() *Stats {
+	return &Stats{}
+}
+
+func (s *Stats) Record(r GameRecord) {
+	s.Games = append(s.Games, r)
+}
+
+type StatsSummary struct {
+	Played int
+	Won    int
+	// WinRate is the share of played games that were solved, from 0 to 1.
+	WinRate     float64
+	AverageTime time.Duration
+	BestTime    time.Duration
+	BestScore   int
+	// CurrentStreak counts the daily puzzles solved on consecutive days up to
+	// today, or up to yesterday while today's is still open.
+	CurrentStreak int
+	LongestStreak int
+}
+
+// Summary adds up the recorded games. Times only count solved games.
+func (s *Stats) Summary(today time.Time) StatsSummary {
+	sum := StatsSummary{Played: len(s.Games)}
+	var total time.Duration
+	for _, r := range s.Games {
+		if !r.Solved {
+			continue
+		}
+		sum.Won += 1
+		total += r.Time
+		if sum.BestTime == 0 || r.Time < sum.BestTime {
+			sum.BestTime = r.Time
+		}
+		sum.BestScore = max(sum.BestScore, r.Score)
+	}
+	if sum.Played > 0 {
+		sum.WinRate = float64(sum.Won) / float64(sum.Played)
+	}
+	if sum.Won > 0 {
+		sum.AverageTime = total / time.Duration(sum.Won)
+	}
+	sum.CurrentStreak, sum.LongestStreak = s.streaks(today)
+	return sum
+}
+
+// streaks returns the current and longest runs of daily puzzles solved on
+// consecutive days.
+func (s *Stats) streaks(today time.Time) (int, int) {
+	var days []time.Time
+	for _, r := range s.Games {
+		if !r.Solved || r.Daily == "" {
+			continue
+		}
+		if d, err := time.Parse(DATE_FORMAT, r.Daily); err == nil {
+			days = append(days, d)
+		}
+	}
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+	days = slices.Compact(days)
+
+	longest, run := 0, 0
+	for i, d := range days {
+		if i > 0 && d.Sub(days[i-1]) == 24*time.Hour {
+			run += 1
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+
+	// the run only counts as current if it reaches today or yesterday
+	current := 0
+	if len(days) > 0 {
+		last, _ := time.Parse(DATE_FORMAT, DailyDate(today))
+		if gap := last.Sub(days[len(days)-1]); gap <= 24*time.Hour {
+			current = run
+		}
+	}
+	return current, longest
+}
+
+// TimeHistogram counts the solved games in each HISTOGRAM_WIDTH of solve time.
+func (s *Stats) TimeHistogram() []int {
+	buckets := make([]int, HISTOGRAM_BUCKETS)
+	for _, r := range s.Games {
+		if r.Solved {
+			buckets[min(int(r.Time/HISTOGRAM_WIDTH), HISTOGRAM_BUCKETS-1)] += 1
+		}
+	}
+	return buckets
+}
+
+// DifficultyCounts returns the number of solved games rated EASY through
+// EXPERT, in that order.
+func (s *Stats) DifficultyCounts() []int {
+	rated := DIFFICULTIES[1:]
+	counts := make([]int, len(rated))
+	for _, r := range s.Games {
+		if i := slices.Index(rated, r.Difficulty); r.Solved && i != -1 {
+			counts[i] += 1
+		}
+	}
+	return counts
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type LineStatus int
+
+const (
+	// LINE_EXACT means the union of the line is its target.
+	LINE_EXACT LineStatus = iota
+	// LINE_MISSING means some target letters are missing and nothing is extra.
+	LINE_MISSING
+	// LINE_EXTRA means every target letter is there, along with others.
+	LINE_EXTRA
+	// LINE_MIXED means letters are both missing and extra.
+	LINE_MIXED
+)
+
+type LineResult struct {
+	Status LineStatus
+	// Missing holds the target letters not in the line, Extra the letters in
+	// the line that aren't in the target, both in the order they first appear.
+	Missing string
+	Extra   string
+}
+
+// CellConflict is a cell holding letters that its row or column can't have.
+type CellConflict struct {
+	Index   int
+	Set     string
+	Letters string
+}
+
+// Validation explains how the board compares to the targets.
+type Validation struct {
+	Rows int
+	// Lines holds the rows followed by the columns, like Game.Targets.
+	Lines     []LineResult
+	Conflicts []CellConflict
+	// Complete is set when every cell holds a set.
+	Complete bool
+	// Solved is set when the board is complete and every line is exact. This
+	// only depends on the targets, not on the generated solution.
+	Solved bool
+	// Alternate is set when the board is solved but differs from the
+	// generated solution, which can happen with hand-made puzzles.
+	Alternate bool
+}
+
+// Validate checks the board against the targets.
+func (g *Game) Validate() Validation {
+	v := Validation{
+		Rows:     g.Rows,
+		Lines:    make([]LineResult, len(g.Targets)),
+		Complete: !slices.Contains(g.Slots, ""),
+	}
+
+	lines := make([]string, len(g.Targets))
+	for i, s := range g.Slots {
+		lines[g.RowTarget(i)] += s
+		lines[g.ColTarget(i)] += s
+	}
+	exact := true
+	for i, t := range g.Targets {
+		l := LineResult{
+			Missing: lettersNotIn(t, lines[i]),
+			Extra:   lettersNotIn(lines[i], t),
+		}
+		switch {
+		case l.Missing != "" && l.Extra != "":
+			l.Status = LINE_MIXED
+		case l.Missing != "":
+			l.Status = LINE_MISSING
+		case l.Extra != "":
+			l.Status = LINE_EXTRA
+		}
+		if l.Status != LINE_EXACT {
+			exact = false
+		}
+		v.Lines[i] = l
+	}
+
+	for i, s := range g.Slots {
+		row, col := g.Targets[g.RowTarget(i)], g.Targets[g.ColTarget(i)]
+		letters := ""
+		for _, r := range s {
+			if !strings.ContainsRune(row, r) || !strings.ContainsRune(col, r) {
+				letters += string(r)
+			}
+		}
+		if letters != "" {
+			v.Conflicts = append(v.Conflicts, CellConflict{Index: i, Set: s, Letters: letters})
+		}
+	}
+
+	v.Solved = v.Complete && exact
+	v.Alternate = v.Solved && len(g.Solution) > 0 && !slices.Equal(g.Slots, g.Solution)
+	return v
+}
+
+// Problems describes what keeps the board from being solved, one line or
+// cell at a time, with lines first.
+func (v Validation) Problems() []string {
+	var problems []string
+	for i, l := range v.Lines {
+		name := fmt.Sprintf("row %d", i+1)
+		if i >= v.Rows {
+			name = fmt.Sprintf("column %d", i-v.Rows+1)
+		}
+		switch l.Status {
+		case LINE_MISSING:
+			problems = append(problems, fmt.Sprintf("%s is missing %s", name, l.Missing))
+		case LINE_EXTRA:
+			problems = append(problems, fmt.Sprintf("%s has extra %s", name, l.Extra))
+		case LINE_MIXED:
+			problems = append(problems, fmt.Sprintf("%s is missing %s and has extra %s", name, l.Missing, l.Extra))
+		}
+	}
+	for _, c := range v.Conflicts {
+		problems = append(problems, fmt.Sprintf("%s doesn't fit, its row or column has no %s", c.Set, c.Letters))
+	}
+	return problems
+}
+
+// lettersNotIn returns the distinct letters of s that aren't in other.
+func lettersNotIn(s, other string) string {
+	letters := ""
+	for _, r := range s {
+		if !strings.ContainsRune(other, r) && !strings.ContainsRune(letters, r) {
+			letters += string(r)
+		}
+	}
+	return letters
+}
@@ -1,6 +1,12 @@
 package main
 
 import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log"
 	"os"
 
@@ -19,17 +25,11 @@ const GAME_HEIGHT = 720
 
 const SAMPLE_RATE = 48000
 
-type GameState int
-
-const (
-	MENU GameState = iota
-	PLAYING
-	CREDITS
-)
+//go:embed credits.md
+var credits string
 
 type EbitenGame struct {
 	ScaledScreen *ui.ScaledScreen
-	gameState    GameState
 	SceneManager *scene.SceneManager
 }
 
@@ -40,7 +40,13 @@ func (g *EbitenGame) Update() error {
 		}
 	}
 
+	ui.UpdatePointer()
+	ui.UpdateGamepads()
 	g.SceneManager.Update()
+	if g.SceneManager.Quit || ebiten.IsWindowBeingClosed() {
+		g.SceneManager.Close()
+		return ebiten.Termination
+	}
 	return nil
 }
 
@@ -64,8 +70,52 @@ func (g *EbitenGame) LayoutF(outsideWidth, outsideHeight float64) (screenWidth,
 	return canvasWidth, canvasHeight
 }
 
+var puzzleFile = flag.String("puzzle", "", "load a puzzle from a JSON file instead of generating one")
+var puzzleRecord = flag.Int("record", 1, "which puzzle to load when the -puzzle file holds several, counting from 1")
+
+// readPuzzle loads the puzzle at record from a file of one or more JSON
+// puzzles, such as the JSON lines written by gridder-gen.
+func readPuzzle(name string, record int) (*core.Game, error) {
+	if record < 1 {
+		return nil, fmt.Errorf("record %d: records count from 1", record)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	for n := 1; ; n++ {
+		var data json.RawMessage
+		err := dec.Decode(&data)
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s has %d puzzles, no record %d", name, n-1, record)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: puzzle %d: %w", name, n, err)
+		}
+		if n == record {
+			game, err := core.Unmarshal(data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: puzzle %d: %w", name, n, err)
+			}
+			return game, nil
+		}
+	}
+}
+
 func main() {
+	flag.Parse()
+
 	game := core.NewGame()
+	if *puzzleFile != "" {
+		var err error
+		game, err = readPuzzle(*puzzleFile, *puzzleRecord)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	// create a new text renderer and configure it
 	txtRenderer := etxt.NewRenderer()
@@ -78,17 +128,29 @@ func main() {
 
 	g := &EbitenGame{
 		ScaledScreen: scaledScreen,
-		gameState:    MENU,
 	}
+	settings := &scene.Settings{}
 	sm := scene.NewSceneManager()
-	gameScene := scene.NewGameScene(game)
+	gameScene := scene.NewGameScene(game, settings)
 	sm.AddScene("game", gameScene)
+	sm.AddScene("pause", scene.NewPauseScene(gameScene))
+	sm.AddScene("menu", scene.NewMenuScene(gameScene))
+	sm.AddScene("settings", scene.NewSettingsScene(settings))
+	sm.AddScene("stats", scene.NewStatsScene(gameScene.Stats))
+	sm.AddScene("credits", scene.NewCreditsScene(credits))
 	g.SceneManager = sm
-	g.SceneManager.SwitchToScene("game")
+	// a puzzle given on the command line is played straight away, a saved
+	// game waits behind Continue in the menu
+	if *puzzleFile != "" {
+		g.SceneManager.SwitchToScene("game")
+	} else {
+		g.SceneManager.SwitchToScene("menu")
+	}
 
 	ebiten.SetWindowSize(GAME_WIDTH, GAME_HEIGHT)
 	ebiten.SetWindowTitle("Gridder Union")
-	if err := ebiten.RunGame(g); err != nil {
+	ebiten.SetWindowClosingHandled(true)
+	if err := ebiten.RunGame(g); err != nil && err != ebiten.Termination {
 		log.Fatal(err)
 	}
 }
@@ -14,7 +14,19 @@ func (b *BaseScene) Draw(screen *ui.ScaledScreen) {
 
 }
 
-func (b *BaseScene) OnSwitch() {
+func (b *BaseScene) OnEnter() {
+
+}
+
+func (b *BaseScene) OnExit() {
+
+}
+
+func (b *BaseScene) OnPause() {
+
+}
+
+func (b *BaseScene) OnResume() {
 
 }
 
@@ -0,0 +1,50 @@
+package scene
+
+import (
+	"image/color"
+
+	"github.com/prizelobby/union-gridder/ui"
+)
+
+// ConfirmScene is a yes/no dialog. It pops itself when answered, then calls
+// OnYes if the answer was yes.
+type ConfirmScene struct {
+	OverlayScene
+	Message string
+	OnYes   func()
+	Menu    *ui.Menu
+}
+
+func NewConfirmScene(message string, onYes func()) *ConfirmScene {
+	c := &ConfirmScene{
+		Message: message,
+		OnYes:   onYes,
+		Menu:    ui.NewMenu(960/2-130, 720/2+10, 120, 50, 0, "Yes", "No"),
+	}
+	c.Menu.Buttons[1].X += 140
+	// default to the answer that doesn't lose anything
+	c.Menu.Selected = 1
+	return c
+}
+
+func (c *ConfirmScene) Update() {
+	answer := c.Menu.Update()
+	if isBackPressed() {
+		answer = 1
+	}
+	switch answer {
+	case 0:
+		c.SceneManager.PopScene()
+		if c.OnYes != nil {
+			c.OnYes()
+		}
+	case 1:
+		c.SceneManager.PopScene()
+	}
+}
+
+func (c *ConfirmScene) Draw(screen *ui.ScaledScreen) {
+	drawPanel(screen, 960/2-220, 720/2-100, 440, 200)
+	screen.DrawTextCenteredAt(c.Message, 28, 960/2, 720/2-40, color.Black)
+	c.Menu.Draw(screen)
+}
@@ -0,0 +1,63 @@
+package scene
+
+import (
+	"image/color"
+	"regexp"
+	"strings"
+
+	"github.com/prizelobby/union-gridder/ui"
+)
+
+// markdownLink matches [text](url) so that only the text is shown.
+var markdownLink = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+
+type creditsLine struct {
+	text string
+	size float64
+}
+
+type CreditsScene struct {
+	BaseScene
+	lines []creditsLine
+	Back  *ui.Menu
+}
+
+// NewCreditsScene renders the given markdown, turning headings into larger
+// text and links into their text.
+func NewCreditsScene(markdown string) *CreditsScene {
+	c := &CreditsScene{
+		Back: ui.NewMenu(960/2-100, 640, 200, 50, 0, "Back"),
+	}
+	for _, l := range strings.Split(markdown, "\n") {
+		l = strings.TrimSpace(markdownLink.ReplaceAllString(l, "$1"))
+		size := 22.0
+		switch {
+		case strings.HasPrefix(l, "### "):
+			size = 26
+		case strings.HasPrefix(l, "## "):
+			size = 32
+		case strings.HasPrefix(l, "# "):
+			size = 40
+		}
+		c.lines = append(c.lines, creditsLine{text: strings.TrimLeft(l, "# "), size: size})
+	}
+	return c
+}
+
+func (c *CreditsScene) Update() {
+	if c.Back.Update() == 0 || isBackPressed() {
+		c.SceneManager.SwitchToSceneWith("menu", SLIDE_OUT)
+	}
+}
+
+func (c *CreditsScene) Draw(screen *ui.ScaledScreen) {
+	screen.Screen.Fill(color.RGBA{230, 228, 213, 255})
+	y := 60.0
+	for _, l := range c.lines {
+		if l.text != "" {
+			screen.DrawTextCenteredAt(l.text, l.size, 960/2, int(y), color.Black)
+		}
+		y += l.size * 1.4
+	}
+	c.Back.Draw(screen)
+}
@@ -1,9 +1,12 @@
 package scene
 
 import (
+	"fmt"
 	"image/color"
+	"log"
 	"slices"
-	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/prizelobby/union-gridder/core"
 
@@ -16,62 +19,285 @@ import (
 const SET_START_X = 75
 const SET_START_Y = 145
 
+// TRAY_ROWS is the number of sets that fit in one column of the tray.
+const TRAY_ROWS = 11
+
+const GRID_TOP = 120
+
+// GRID_SPAN is the space reserved for the grid, and MAX_CELL_PITCH the
+// distance between neighbouring cells on a 3x3 grid.
+const GRID_SPAN = 540
+const MAX_CELL_PITCH = 180
+
+// TAP_DISTANCE is how far a press can move and still count as a tap rather
+// than a drag.
+const TAP_DISTANCE = 10
+
+// Sets glide to their place over GLIDE_DURATION, and bounce back to the tray
+// over BOUNCE_DURATION after a drop outside the grid.
+const GLIDE_DURATION = 250 * time.Millisecond
+const BOUNCE_DURATION = 600 * time.Millisecond
+
 type GameScene struct {
 	BaseScene
 	Game          *core.Game
 	Setsprites    []*ui.SetSprite
 	Droplocations []*ui.DropLocation
-	MatchColors   [6][]color.Color
-	ExtraColors   [9][]color.Color
+	MatchColors   [][]color.Color
+	ExtraColors   [][]color.Color
 	Stroke        *ui.Stroke
+	CodeInput     *ui.TextInput
+	HintButton    *ui.Button
+	DailyLog      *core.DailyLog
+	Stats         *core.Stats
+	Settings      *Settings
+	// Started is set once the player has opened the game, so the menu can
+	// offer to continue it.
+	Started bool
+	// hint is the last hint given, highlighted until the board changes
+	hint *core.Hint
+	// sprites holds every set sprite, in the order of Game.Sets
+	sprites []*ui.SetSprite
+	// dragFrom is the cell the dragged set was picked up from, or -1 for the tray
+	dragFrom int
+	// cursor is used for keyboard play and only drawn once a key has moved it.
+	// held is the set picked up with the keyboard or selected with a tap, from
+	// cell heldFrom or -1 for the tray.
+	cursor        cursor
+	showCursor    bool
+	held          string
+	heldFrom      int
+	trayPerColumn int
+	// savedAt is the elapsed time when the game was last saved
+	savedAt time.Duration
+	// recorded is set once the game is in the stats
+	recorded bool
+	// validation is the state of the board as of the last change
+	validation core.Validation
+	// message is shown under the title for messageTicks updates
+	message      string
+	messageColor color.Color
+	messageTicks int
 }
 
-func NewGameScene(game *core.Game) *GameScene {
+func NewGameScene(game *core.Game, settings *Settings) *GameScene {
 	g := &GameScene{
-		Game: game,
+		Game:       game,
+		CodeInput:  ui.NewTextInput(960/2-200, 720/2-70, 400, 140, "Enter a puzzle code", 16),
+		HintButton: ui.NewButton(20, 630, 150, 40, "Hint [H]"),
+		DailyLog:   loadDailyLog(),
+		Stats:      loadStats(),
+		Settings:   settings,
 	}
 
-	g.Reset()
+	// a game that already holds a puzzle, e.g. one loaded from a file, is kept,
+	// otherwise the game saved last time is restored
+	if len(game.Sets) > 0 || loadSavedGame(game) {
+		g.Started = true
+		g.LoadBoard()
+	} else if err := g.Reset(); err != nil {
+		log.Fatal(err)
+	}
 	return g
 }
 
-func (g *GameScene) Reset() {
-	g.Game.Reset()
-	setSprites := make([]*ui.SetSprite, 0, core.NUM_SETS)
+// save stores the game so it can be continued after a restart. Nothing is
+// saved before the player has started playing.
+func (g *GameScene) save() {
+	if !g.Started {
+		return
+	}
+	saveGame(g.Game)
+	g.savedAt = g.Game.Elapsed
+}
 
-	for i, s := range g.Game.Sets {
-		setSprites = append(setSprites, &ui.SetSprite{
-			SpriteName: s,
-			X:          SET_START_X,
-			Y:          SET_START_Y + float64(i)*50,
-		})
+// Reset starts a new random game with the settings. If no puzzle can be
+// generated the error is shown and the current game carries on.
+func (g *GameScene) Reset() error {
+	next := core.NewGame()
+	next.Rand = g.Game.Rand
+	next.Options = g.Game.Options
+	next.Rows, next.Cols = g.Settings.Rows(), g.Settings.Cols()
+	next.Difficulty = g.Settings.Difficulty
+	if err := next.Reset(); err != nil {
+		g.showMessage(err.Error(), color.RGBA{220, 90, 80, 255})
+		return err
 	}
+	g.replaceGame(next)
+	return nil
+}
 
-	dropLocations := make([]*ui.DropLocation, 0, 9)
-	for i := 0; i < 3; i++ {
-		for j := 0; j < 3; j++ {
+// replaceGame switches to a new game, recording the old one in the stats if
+// it was abandoned.
+func (g *GameScene) replaceGame(next *core.Game) {
+	g.abandon()
+	g.Game = next
+	g.LoadBoard()
+}
+
+// ConfirmNewGame starts a new game, asking first if that would throw away a
+// board the player has been working on.
+func (g *GameScene) ConfirmNewGame() {
+	if len(g.Game.History) == 0 || g.Game.Solved {
+		g.Reset()
+		return
+	}
+	g.SceneManager.Push(NewConfirmScene("Abandon this puzzle?", func() { g.Reset() }))
+}
+
+func (g *GameScene) StartDaily() {
+	next := core.NewGame()
+	if err := next.ResetDaily(time.Now()); err != nil {
+		g.showMessage(err.Error(), color.RGBA{220, 90, 80, 255})
+		return
+	}
+	g.replaceGame(next)
+}
+
+// LoadBoard lays out the sprites and drop locations for the game's current
+// puzzle, putting sets already in a slot into their cell.
+func (g *GameScene) LoadBoard() {
+	g.Stroke = nil
+	g.dragFrom = -1
+	g.hint = nil
+	g.cursor = cursor{}
+	g.recorded = false
+
+	pitch, cell := g.cellPitch(), g.cellSize()
+	gridX := g.gridX()
+	dropLocations := make([]*ui.DropLocation, 0, g.Game.NumSets())
+	for i := 0; i < g.Game.Cols; i++ {
+		for j := 0; j < g.Game.Rows; j++ {
 			dropLocations = append(dropLocations, &ui.DropLocation{
-				X:     960/2 - 180 - 60 + float64(i)*180,
-				Y:     120 + float64(j)*180,
-				W:     120,
-				H:     120,
-				Index: i + 3*j,
+				X:     gridX + float64(i)*pitch,
+				Y:     GRID_TOP + float64(j)*pitch,
+				W:     cell,
+				H:     cell,
+				Index: i + g.Game.Cols*j,
 			})
 		}
 	}
 
-	matchColors := [6][]color.Color{}
-	for i := range 6 {
-		matchColors[i] = make([]color.Color, len(g.Game.Targets[i]))
+	sprites := make([]*ui.SetSprite, 0, len(g.Game.Sets))
+	for _, s := range g.Game.Sets {
+		sprites = append(sprites, &ui.SetSprite{
+			SpriteName: s,
+		})
+	}
+
+	matchColors := make([][]color.Color, len(g.Game.Targets))
+	for i := range matchColors {
+		matchColors[i] = make([]color.Color, utf8.RuneCountInString(g.Game.Targets[i]))
 		for j := range len(matchColors[i]) {
 			matchColors[i][j] = color.RGBA{0, 0, 0, 255}
 		}
 	}
-	g.ExtraColors = [9][]color.Color{}
+	g.ExtraColors = make([][]color.Color, g.Game.NumSets())
 
-	g.Setsprites = setSprites
+	g.sprites = sprites
 	g.Droplocations = dropLocations
 	g.MatchColors = matchColors
+	g.syncBoard()
+	// a new board starts in place rather than gliding in
+	for _, sprite := range g.sprites {
+		sprite.FinishGlide()
+	}
+}
+
+// syncBoard moves every sprite to where the game's slots say it is, with the
+// rest lined up in the tray.
+func (g *GameScene) syncBoard() {
+	g.hint = nil
+	g.held = ""
+	for _, loc := range g.Droplocations {
+		loc.SetSprite = g.spriteNamed(g.Game.Slots[loc.Index])
+		if loc.SetSprite != nil {
+			x, y := loc.SpritePosition()
+			loc.SetSprite.GlideTo(x, y, GLIDE_DURATION, ui.EaseOutCubic)
+		}
+	}
+	g.Setsprites = make([]*ui.SetSprite, 0, len(g.sprites))
+	for _, sprite := range g.sprites {
+		if !slices.Contains(g.Game.Slots, sprite.SpriteName) {
+			g.Setsprites = append(g.Setsprites, sprite)
+		}
+	}
+	g.arrangeTray()
+	g.clampCursor()
+	g.RecalculateMatches()
+	g.validate()
+	g.save()
+}
+
+// validate explains why a full board isn't accepted.
+func (g *GameScene) validate() {
+	g.validation = g.Game.Validate()
+	if !g.validation.Complete || g.validation.Solved {
+		return
+	}
+	problems := g.validation.Problems()
+	msg := "Not quite"
+	if len(problems) > 0 {
+		msg = problems[0]
+	}
+	if len(problems) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(problems)-1)
+	}
+	g.showMessage(msg, color.RGBA{220, 90, 80, 255})
+}
+
+func (g *GameScene) spriteNamed(name string) *ui.SetSprite {
+	for _, sprite := range g.sprites {
+		if sprite.SpriteName == name {
+			return sprite
+		}
+	}
+	return nil
+}
+
+// cellPitch is the distance between neighbouring cells, shrinking the grid
+// so that it always fits in GRID_SPAN.
+func (g *GameScene) cellPitch() float64 {
+	return min(GRID_SPAN/float64(g.Game.Cols), GRID_SPAN/float64(g.Game.Rows), MAX_CELL_PITCH)
+}
+
+func (g *GameScene) cellSize() float64 {
+	return g.cellPitch() * 2 / 3
+}
+
+func (g *GameScene) gridWidth() float64 {
+	return float64(g.Game.Cols)*g.cellPitch() - g.cellPitch() + g.cellSize()
+}
+
+func (g *GameScene) gridHeight() float64 {
+	return float64(g.Game.Rows)*g.cellPitch() - g.cellPitch() + g.cellSize()
+}
+
+func (g *GameScene) gridX() float64 {
+	return 960/2 - g.gridWidth()/2
+}
+
+// arrangeTray lines up the sets in the tray, splitting them evenly into as
+// many columns as needed.
+func (g *GameScene) arrangeTray() {
+	columns := (len(g.Setsprites) + TRAY_ROWS - 1) / TRAY_ROWS
+	perColumn := TRAY_ROWS
+	if columns > 0 {
+		perColumn = (len(g.Setsprites) + columns - 1) / columns
+	}
+	g.trayPerColumn = perColumn
+	for i, sprite := range g.Setsprites {
+		x := SET_START_X + float64(i/perColumn)*80
+		y := SET_START_Y + float64(i%perColumn)*50
+		sprite.GlideTo(x, y, GLIDE_DURATION, ui.EaseOutCubic)
+	}
+}
+
+func targetTextSize(target string) float64 {
+	if utf8.RuneCountInString(target) > 8 {
+		return 24
+	}
+	return 32
 }
 
 func (g *GameScene) Draw(screen *ui.ScaledScreen) {
@@ -80,18 +306,48 @@ func (g *GameScene) Draw(screen *ui.ScaledScreen) {
 	for _, loc := range g.Droplocations {
 		loc.Draw(screen, g.ExtraColors[loc.Index])
 	}
+	g.drawHint(screen)
+	g.drawConflicts(screen)
+	g.drawCursor(screen)
 	screen.DrawTextCenteredAt("Gridder Union", 64, 960/2, 60, color.Black)
-	screen.DrawTextWithColors(string(g.Game.Targets[0]), 32, 740, 120+60-ui.SetSpriteHeight/2, g.MatchColors[0])
-	screen.DrawTextWithColors(string(g.Game.Targets[1]), 32, 740, 120+180+60-ui.SetSpriteHeight/2, g.MatchColors[1])
-	screen.DrawTextWithColors(string(g.Game.Targets[2]), 32, 740, 120+2*180+60-ui.SetSpriteHeight/2, g.MatchColors[2])
-	screen.DrawTextCenteredAtWithColors(string(g.Game.Targets[3]), 32, 960/2-180, 630, g.MatchColors[3])
-	screen.DrawTextCenteredAtWithColors(string(g.Game.Targets[4]), 32, 960/2, 630, g.MatchColors[4])
-	screen.DrawTextCenteredAtWithColors(string(g.Game.Targets[5]), 32, 960/2+180, 630, g.MatchColors[5])
+	pitch, cell := g.cellPitch(), g.cellSize()
+	gridX := g.gridX()
+	for j := range g.Game.Rows {
+		t := g.Game.Targets[j]
+		screen.DrawTextWithColors(t, targetTextSize(t), int(gridX+g.gridWidth()+20), int(GRID_TOP+float64(j)*pitch+cell/2-ui.SetSpriteHeight/2), g.MatchColors[j])
+	}
+	for i := range g.Game.Cols {
+		t := g.Game.Targets[g.Game.Rows+i]
+		screen.DrawTextCenteredAtWithColors(t, targetTextSize(t), int(gridX+float64(i)*pitch+cell/2), int(GRID_TOP+g.gridHeight()+30), g.MatchColors[g.Game.Rows+i])
+	}
 
 	if g.Game.Solved {
-		screen.DrawTextCenteredAt("You solved the puzzle!", 40, 960/2, 680, color.RGBA{90, 190, 90, 255})
+		screen.DrawTextCenteredAt(fmt.Sprintf("Solved! Score %d", g.Game.Score()), 40, 960/2, 680, color.RGBA{90, 190, 90, 255})
 	}
-	screen.DrawText("New Game [Enter]", 24, 744, 670, color.Black)
+	if g.Game.Daily != "" {
+		status := "Daily " + g.Game.Daily
+		if r, ok := g.DailyLog.Completed(g.Game.Daily); ok {
+			status += ", best " + formatDuration(r.Time)
+		}
+		screen.DrawText(status, 24, 20, 20, color.Black)
+	} else {
+		screen.DrawText(fmt.Sprintf("%dx%d, rated %s", g.Game.Rows, g.Game.Cols, g.Game.Rating.Difficulty()), 24, 20, 20, color.Black)
+	}
+	screen.DrawText(formatDuration(g.Game.Elapsed), 32, 830, 20, color.Black)
+	screen.DrawText(fmt.Sprintf("Moves %d", g.Game.Moves), 20, 830, 60, color.RGBA{90, 90, 90, 255})
+	screen.DrawText(fmt.Sprintf("Score %d", g.Game.Score()), 20, 830, 85, color.RGBA{90, 90, 90, 255})
+	if code, err := g.Game.ShareCode(); err == nil {
+		screen.DrawText("Code "+code, 20, 20, 50, color.RGBA{90, 90, 90, 255})
+	}
+	screen.DrawText("Pause [Esc]", 24, 744, 610, color.Black)
+	screen.DrawText("Enter Code [C]", 24, 744, 640, color.Black)
+	if g.messageTicks > 0 {
+		screen.DrawTextCenteredAt(g.message, 24, 960/2, 100, g.messageColor)
+	}
+	g.HintButton.Label = fmt.Sprintf("Hint [H] (%d)", g.Game.HintsUsed)
+	g.HintButton.Draw(screen)
+	screen.DrawText("New Game [N]", 24, 744, 670, color.Black)
+	screen.DrawText("Undo [Ctrl+Z]  Redo [Ctrl+Y]", 18, 20, 690, color.RGBA{90, 90, 90, 255})
 
 	for _, sprite := range g.Setsprites {
 		sprite.Draw(screen)
@@ -99,20 +355,150 @@ func (g *GameScene) Draw(screen *ui.ScaledScreen) {
 	if g.Stroke != nil {
 		g.Stroke.DraggingObject.(*ui.SetSprite).Draw(screen)
 	}
+	g.CodeInput.Draw(screen)
+}
+
+func (g *GameScene) showMessage(message string, c color.Color) {
+	g.message = message
+	g.messageColor = c
+	g.messageTicks = 3 * ebiten.TPS()
+}
+
+func (g *GameScene) ShowHint() {
+	h, ok := g.Game.Hint()
+	if !ok {
+		return
+	}
+	g.hint = &h
+	g.showMessage(h.String(), color.RGBA{60, 110, 160, 255})
+}
+
+// drawHint outlines the cell or row the current hint is about.
+func (g *GameScene) drawHint(screen *ui.ScaledScreen) {
+	if g.hint == nil {
+		return
+	}
+	hintColor := color.RGBA{60, 110, 160, 255}
+	switch g.hint.Level {
+	case core.WRONG_CELL:
+		hintColor = color.RGBA{220, 90, 80, 255}
+	case core.SET_ROW:
+		y := GRID_TOP + float64(g.hint.Row)*g.cellPitch()
+		screen.DrawUnfilledRect(g.gridX()-10, y-10, g.gridWidth()+20, g.cellSize()+20, 4, hintColor)
+		return
+	}
+	for _, loc := range g.Droplocations {
+		if loc.Index == g.hint.Index {
+			screen.DrawUnfilledRect(loc.X-6, loc.Y-6, loc.W+12, loc.H+12, 4, hintColor)
+		}
+	}
+}
+
+// drawConflicts outlines the cells holding letters their row or column can't
+// have, once the board is full.
+func (g *GameScene) drawConflicts(screen *ui.ScaledScreen) {
+	if !g.validation.Complete || g.validation.Solved {
+		return
+	}
+	for _, c := range g.validation.Conflicts {
+		for _, loc := range g.Droplocations {
+			if loc.Index == c.Index {
+				screen.DrawUnfilledRect(loc.X-4, loc.Y-4, loc.W+8, loc.H+8, 3, color.RGBA{220, 90, 80, 255})
+			}
+		}
+	}
+}
+
+func (g *GameScene) updateCodeInput() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.CodeInput.Close()
+		return
+	}
+	if g.CodeInput.Update() {
+		g.CodeInput.Close()
+		// the current game carries on if the code is bad
+		next := core.NewGame()
+		if err := next.LoadCode(g.CodeInput.Text); err != nil {
+			g.showMessage(err.Error(), color.RGBA{220, 90, 80, 255})
+			return
+		}
+		g.replaceGame(next)
+	}
 }
 
 func (g *GameScene) Update() {
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
-		g.Reset()
+	if g.messageTicks > 0 {
+		g.messageTicks -= 1
+	}
+	for _, sprite := range g.sprites {
+		sprite.Update()
+	}
+	if g.CodeInput.Active {
+		g.updateCodeInput()
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyC) && g.Stroke == nil {
+		g.CodeInput.Open()
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyN) && g.Stroke == nil {
+		g.ConfirmNewGame()
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) && g.held != "" {
+		g.held = ""
+		return
+	}
+	if isPausePressed() && g.Stroke == nil {
+		g.SceneManager.PushScene("pause")
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyH) && g.Stroke == nil {
+		g.ShowHint()
+	}
+	// the clock stops while the window is in the background
+	if ebiten.IsFocused() {
+		g.Game.Advance(time.Second / time.Duration(ebiten.TPS()))
+	}
+	if g.Game.Elapsed-g.savedAt >= AUTOSAVE_INTERVAL {
+		g.save()
+	}
+
+	if g.Stroke == nil && !g.Game.Solved && isShortcutPressed(ebiten.KeyZ) {
+		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+			g.Game.Redo()
+		} else {
+			g.Game.Undo()
+		}
+		g.syncBoard()
+	}
+	if g.Stroke == nil && !g.Game.Solved && isShortcutPressed(ebiten.KeyY) {
+		g.Game.Redo()
+		g.syncBoard()
+	}
+
+	if g.Stroke == nil && !g.Game.Solved {
+		g.updateKeyboard()
+		g.updateGamepad()
 	}
 
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+	if ui.IsPointerJustPressed() {
+		g.showCursor = false
 		if !g.Game.Solved {
-			cursorX, cursorY := ui.AdjustedCursorPosition()
+			cursorX, cursorY := ui.PointerPosition()
+			if g.HintButton.Contains(cursorX, cursorY) {
+				g.ShowHint()
+				return
+			}
+			if g.held != "" {
+				g.tapWithHeld(cursorX, cursorY)
+				return
+			}
 			selectedIndex := -1
 			for i, setSprite := range g.Setsprites {
 				if setSprite.Contains(cursorX, cursorY) {
 					g.Stroke = ui.NewStroke(cursorX, cursorY, setSprite)
+					g.dragFrom = -1
 					selectedIndex = i
 					break
 				}
@@ -123,9 +509,8 @@ func (g *GameScene) Update() {
 			for _, loc := range g.Droplocations {
 				if loc.SetSprite != nil && loc.SetSprite.Contains(cursorX, cursorY) {
 					g.Stroke = ui.NewStroke(cursorX, cursorY, loc.SetSprite)
+					g.dragFrom = loc.Index
 					loc.SetSprite = nil
-					g.Game.SetSlot(loc.Index, "")
-					g.RecalculateMatches()
 					break
 				}
 			}
@@ -133,50 +518,121 @@ func (g *GameScene) Update() {
 	}
 
 	if g.Stroke != nil {
-		cursorX, cursorY := ui.AdjustedCursorPosition()
+		cursorX, cursorY := ui.PointerPosition()
 		g.Stroke.Update(cursorX, cursorY)
 
-		if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
-			dropTaken := false
+		if ui.IsPointerJustReleased() {
+			sprite := g.Stroke.DraggingObject.(*ui.SetSprite)
+			tapped := g.Stroke.Distance() < TAP_DISTANCE
+			target := -1
 			for _, loc := range g.Droplocations {
 				if loc.Contains(cursorX, cursorY) {
-					g.Stroke.DraggingObject.MoveTo(loc.X+float64(loc.W)/2-ui.SetSpriteWidth/2, loc.Y+float64(loc.H)/2-ui.SetSpriteHeight/2)
-
-					if loc.SetSprite != nil {
-						// If there's already a sprite in the drop location, we need to remove it first
-						g.Setsprites = append(g.Setsprites, loc.SetSprite)
-						slices.SortFunc(g.Setsprites, func(a, b *ui.SetSprite) int {
-							return strings.Compare(a.SpriteName, b.SpriteName)
-						})
-					}
-
-					loc.SetSprite = g.Stroke.DraggingObject.(*ui.SetSprite)
-					g.Game.SetSlot(loc.Index, g.Stroke.DraggingObject.(*ui.SetSprite).SpriteName)
-					dropTaken = true
+					target = loc.Index
 					break
 				}
 			}
-			if !dropTaken {
-				g.Setsprites = append(g.Setsprites, g.Stroke.DraggingObject.(*ui.SetSprite))
-				slices.SortFunc(g.Setsprites, func(a, b *ui.SetSprite) int {
-					return strings.Compare(a.SpriteName, b.SpriteName)
-				})
+			switch {
+			case tapped:
+				// a tap only selects the set, which is placed by tapping a cell
+			case target != -1:
+				g.drop(target, sprite.SpriteName, g.dragFrom)
+			case g.dragFrom != -1:
+				g.Game.Remove(g.dragFrom)
 			}
-			for i, loc := range g.Setsprites {
-				loc.X = SET_START_X
-				loc.Y = SET_START_Y + float64(i)*50
-			}
-			g.RecalculateMatches()
+			dragFrom := g.dragFrom
 
 			g.Stroke.DraggingObject = nil
 			g.Stroke.Release()
 			g.Stroke = nil
+			g.dragFrom = -1
+			g.syncBoard()
+			g.checkSolved()
+			if target == -1 && !tapped && sprite.Tween != nil {
+				sprite.Tween.Duration = BOUNCE_DURATION
+				sprite.Tween.Easing = ui.EaseOutBounce
+			}
+			if tapped {
+				g.held = sprite.SpriteName
+				g.heldFrom = dragFrom
+			}
+		}
+	}
+}
+
+// drop puts set, picked up from the cell at from or -1 for the tray, into the
+// cell at target. A set moved onto another one swaps places with it, while a
+// set from the tray sends the one it replaces back to the tray.
+func (g *GameScene) drop(target int, set string, from int) {
+	if from != -1 && g.Game.Slots[target] != "" {
+		g.Game.Swap(from, target)
+		return
+	}
+	g.Game.Place(target, set)
+}
+
+// tapWithHeld handles a tap while a set is selected. Tapping a cell places
+// the set there and tapping the tray area sends it back. Tapping another set
+// in the tray selects that one instead, and anything else clears the selection.
+func (g *GameScene) tapWithHeld(x, y float64) {
+	for _, sprite := range g.Setsprites {
+		if sprite.Contains(x, y) && sprite.SpriteName != g.held {
+			g.held = sprite.SpriteName
+			g.heldFrom = -1
+			return
+		}
+	}
+	for _, loc := range g.Droplocations {
+		if loc.Contains(x, y) {
+			g.drop(loc.Index, g.held, g.heldFrom)
+			g.syncBoard()
+			g.checkSolved()
+			return
 		}
 	}
+	if x < g.gridX()-20 && g.heldFrom != -1 {
+		g.Game.Remove(g.heldFrom)
+	}
+	g.syncBoard()
+}
+
+// isShortcutPressed reports whether key was just pressed while holding Ctrl,
+// or Cmd on macOS.
+func isShortcutPressed(key ebiten.Key) bool {
+	return inpututil.IsKeyJustPressed(key) && (ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta))
+}
+
+// checkSolved records a solved puzzle in the stats, and in the daily log if
+// it is a daily puzzle.
+func (g *GameScene) checkSolved() {
+	if !g.Game.Solved || g.recorded {
+		return
+	}
+	g.record()
+	if g.validation.Alternate {
+		g.showMessage("Solved with a different arrangement than the intended one", color.RGBA{90, 190, 90, 255})
+	}
+	if g.Game.Daily != "" {
+		g.DailyLog.Complete(g.Game.Daily, g.Game.Elapsed)
+		saveDailyLog(g.DailyLog)
+	}
+}
+
+// abandon records the current game as lost when it is replaced before being
+// solved. Games without a single move don't count.
+func (g *GameScene) abandon() {
+	if g.Started && !g.recorded && g.Game.Moves > 0 {
+		g.record()
+	}
+}
+
+func (g *GameScene) record() {
+	g.Stats.Record(core.NewGameRecord(g.Game, time.Now()))
+	saveStats(g.Stats)
+	g.recorded = true
 }
 
 func (g *GameScene) RecalculateMatches() {
-	for i := range 6 {
+	for i := range g.Game.Matches {
 		for j, m := range g.Game.Matches[i] {
 			if m {
 				g.MatchColors[i][j] = color.RGBA{90, 190, 90, 255} // Green for matches
@@ -185,7 +641,7 @@ func (g *GameScene) RecalculateMatches() {
 			}
 		}
 	}
-	for i := range 9 {
+	for i := range g.Game.Extras {
 		g.ExtraColors[i] = make([]color.Color, len(g.Game.Extras[i]))
 		for j, m := range g.Game.Extras[i] {
 			if m {
@@ -197,7 +653,20 @@ func (g *GameScene) RecalculateMatches() {
 	}
 }
 
-func (g *GameScene) OnSwitch() {
+// OnPause saves the game and drops a set being dragged back where it came
+// from, since the release would go to the scene on top.
+func (g *GameScene) OnPause() {
+	if g.Stroke != nil {
+		g.Stroke.Release()
+		g.Stroke = nil
+		g.dragFrom = -1
+		g.syncBoard()
+	}
+	g.save()
+}
+
+func (g *GameScene) OnExit() {
+	g.save()
 }
 
 func (g *GameScene) SetSceneManager(sm *SceneManager) {
@@ -0,0 +1,185 @@
+package scene
+
+import (
+	"image/color"
+
+	"github.com/prizelobby/union-gridder/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// cursor is where keyboard play is focused: a set in the tray, or a cell of
+// the grid. Both positions are kept so moving back and forth resumes where
+// the player left off.
+type cursor struct {
+	inGrid bool
+	tray   int
+	cell   int
+}
+
+// updateKeyboard handles keyboard play: the arrow keys move the cursor, Space
+// or Enter picks up and drops a set, 1-9 drop into or jump to a cell and
+// Backspace sends a set back to the tray.
+func (g *GameScene) updateKeyboard() {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp):
+		g.moveCursor(0, -1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		g.moveCursor(0, 1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyLeft):
+		g.moveCursor(-1, 0)
+	case inpututil.IsKeyJustPressed(ebiten.KeyRight):
+		g.moveCursor(1, 0)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		g.showCursor = true
+		g.activateCursor()
+	}
+	for i := range 9 {
+		if inpututil.IsKeyJustPressed(ebiten.Key1+ebiten.Key(i)) || inpututil.IsKeyJustPressed(ebiten.KeyNumpad1+ebiten.Key(i)) {
+			g.targetCell(i)
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
+		g.showCursor = true
+		g.returnToTray()
+	}
+}
+
+// updateGamepad moves the same cursor with the D-pad or left stick. A picks up
+// and drops a set and B sends it back to the tray.
+func (g *GameScene) updateGamepad() {
+	if dx, dy := ui.GamepadDirection(); dx != 0 || dy != 0 {
+		g.moveCursor(dx, dy)
+	}
+	if ui.IsGamepadButtonJustPressed(ebiten.StandardGamepadButtonRightBottom) {
+		g.showCursor = true
+		g.activateCursor()
+	}
+	if ui.IsGamepadButtonJustPressed(ebiten.StandardGamepadButtonRightRight) {
+		g.showCursor = true
+		g.returnToTray()
+	}
+}
+
+func (g *GameScene) moveCursor(dx, dy int) {
+	g.showCursor = true
+	c := &g.cursor
+	if c.inGrid {
+		row, col := c.cell/g.Game.Cols+dy, c.cell%g.Game.Cols+dx
+		if col < 0 && len(g.Setsprites) > 0 {
+			c.inGrid = false
+			return
+		}
+		row = max(0, min(row, g.Game.Rows-1))
+		col = max(0, min(col, g.Game.Cols-1))
+		c.cell = row*g.Game.Cols + col
+		return
+	}
+
+	// the tray is laid out in columns of trayPerColumn sets
+	per := g.trayPerColumn
+	columnStart := c.tray / per * per
+	switch {
+	case dy != 0:
+		c.tray = max(columnStart, min(c.tray+dy, columnStart+per-1, len(g.Setsprites)-1))
+	case dx < 0 && c.tray-per >= 0:
+		c.tray -= per
+	case dx > 0 && c.tray+per < len(g.Setsprites):
+		c.tray += per
+	case dx > 0 && columnStart+per < len(g.Setsprites):
+		c.tray = len(g.Setsprites) - 1
+	case dx > 0:
+		c.inGrid = true
+	}
+}
+
+// activateCursor picks up the set under the cursor, or drops the held set
+// there. Dropping on the tray takes the set off the grid.
+func (g *GameScene) activateCursor() {
+	if g.held == "" {
+		if g.cursor.inGrid {
+			g.held = g.Game.Slots[g.cursor.cell]
+			g.heldFrom = g.cursor.cell
+		} else if g.cursor.tray < len(g.Setsprites) {
+			g.held = g.Setsprites[g.cursor.tray].SpriteName
+			g.heldFrom = -1
+			g.cursor.inGrid = true
+		}
+		return
+	}
+
+	if g.cursor.inGrid {
+		g.drop(g.cursor.cell, g.held, g.heldFrom)
+	} else if g.heldFrom != -1 {
+		g.Game.Remove(g.heldFrom)
+	}
+	g.held = ""
+	g.syncBoard()
+	g.checkSolved()
+}
+
+// targetCell drops the held set, or the set under the cursor in the tray,
+// into the cell at index. With nothing to drop it moves the cursor there.
+func (g *GameScene) targetCell(index int) {
+	if index >= g.Game.NumSets() {
+		return
+	}
+	g.showCursor = true
+	if g.held == "" && !g.cursor.inGrid && g.cursor.tray < len(g.Setsprites) {
+		g.held = g.Setsprites[g.cursor.tray].SpriteName
+		g.heldFrom = -1
+	}
+	g.cursor.inGrid = true
+	g.cursor.cell = index
+	if g.held != "" {
+		g.activateCursor()
+	}
+}
+
+// returnToTray sends the held set, or the set in the cell under the cursor,
+// back to the tray.
+func (g *GameScene) returnToTray() {
+	switch {
+	case g.held != "":
+		if g.heldFrom != -1 {
+			g.Game.Remove(g.heldFrom)
+		}
+		g.held = ""
+	case g.cursor.inGrid:
+		g.Game.Remove(g.cursor.cell)
+	default:
+		return
+	}
+	g.syncBoard()
+}
+
+// clampCursor keeps the cursor on the board after it changes.
+func (g *GameScene) clampCursor() {
+	g.cursor.cell = max(0, min(g.cursor.cell, g.Game.NumSets()-1))
+	g.cursor.tray = max(0, min(g.cursor.tray, len(g.Setsprites)-1))
+	if len(g.Setsprites) == 0 {
+		g.cursor.inGrid = true
+	}
+}
+
+func (g *GameScene) drawCursor(screen *ui.ScaledScreen) {
+	if held := g.spriteNamed(g.held); held != nil {
+		screen.DrawUnfilledRect(held.X-5, held.Y-5, ui.SetSpriteWidth+10, ui.SetSpriteHeight+10, 3, color.RGBA{60, 110, 160, 255})
+	}
+	if !g.showCursor {
+		return
+	}
+	cursorColor := color.RGBA{240, 160, 40, 255}
+	if g.cursor.inGrid {
+		for _, loc := range g.Droplocations {
+			if loc.Index == g.cursor.cell {
+				screen.DrawUnfilledRect(loc.X-10, loc.Y-10, loc.W+20, loc.H+20, 4, cursorColor)
+			}
+		}
+	} else if g.cursor.tray < len(g.Setsprites) {
+		s := g.Setsprites[g.cursor.tray]
+		screen.DrawUnfilledRect(s.X-8, s.Y-8, ui.SetSpriteWidth+16, ui.SetSpriteHeight+16, 4, cursorColor)
+	}
+}
@@ -0,0 +1,84 @@
+package scene
+
+import (
+	"image/color"
+	"runtime"
+
+	"github.com/prizelobby/union-gridder/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	MENU_NEW_GAME = iota
+	MENU_DAILY
+	MENU_CONTINUE
+	MENU_SETTINGS
+	MENU_STATS
+	MENU_CREDITS
+	MENU_QUIT
+)
+
+type MenuScene struct {
+	BaseScene
+	GameScene *GameScene
+	Menu      *ui.Menu
+}
+
+func NewMenuScene(gameScene *GameScene) *MenuScene {
+	m := &MenuScene{
+		GameScene: gameScene,
+		Menu:      ui.NewMenu(960/2-150, 170, 300, 50, 14, "New Game", "Daily Puzzle", "Continue", "Settings", "Statistics", "Credits", "Quit"),
+	}
+	// there is nothing to quit to in the browser
+	if runtime.GOOS == "js" {
+		m.Menu.Buttons = m.Menu.Buttons[:MENU_QUIT]
+	}
+	return m
+}
+
+func (m *MenuScene) Update() {
+	m.Menu.Buttons[MENU_CONTINUE].Disabled = !m.GameScene.Started
+	switch m.Menu.Update() {
+	case MENU_NEW_GAME:
+		m.GameScene.Reset()
+		m.startGame()
+	case MENU_DAILY:
+		m.GameScene.StartDaily()
+		m.startGame()
+	case MENU_CONTINUE:
+		m.startGame()
+	case MENU_SETTINGS:
+		m.SceneManager.SwitchToSceneWith("settings", SLIDE_IN)
+	case MENU_STATS:
+		m.SceneManager.SwitchToSceneWith("stats", SLIDE_IN)
+	case MENU_CREDITS:
+		m.SceneManager.SwitchToSceneWith("credits", SLIDE_IN)
+	case MENU_QUIT:
+		m.SceneManager.Quit = true
+	}
+}
+
+func (m *MenuScene) startGame() {
+	m.GameScene.Started = true
+	m.SceneManager.SwitchToSceneWith("game", WIPE_IN)
+}
+
+func (m *MenuScene) Draw(screen *ui.ScaledScreen) {
+	screen.Screen.Fill(color.RGBA{230, 228, 213, 255})
+	screen.DrawTextCenteredAt("Gridder Union", 64, 960/2, 110, color.Black)
+	m.Menu.Draw(screen)
+}
+
+// isBackPressed reports whether the player asked to leave a screen, with
+// Escape or B on a gamepad.
+func isBackPressed() bool {
+	return inpututil.IsKeyJustPressed(ebiten.KeyEscape) || ui.IsGamepadButtonJustPressed(ebiten.StandardGamepadButtonRightRight)
+}
+
+// isPausePressed reports whether the player asked for the pause menu, with
+// Escape or Start on a gamepad.
+func isPausePressed() bool {
+	return inpututil.IsKeyJustPressed(ebiten.KeyEscape) || ui.IsGamepadButtonJustPressed(ebiten.StandardGamepadButtonCenterRight)
+}
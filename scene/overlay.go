@@ -0,0 +1,25 @@
+package scene
+
+import (
+	"image/color"
+
+	"github.com/prizelobby/union-gridder/ui"
+)
+
+// OverlayScene is embedded by scenes that are drawn over the scene under
+// them, like the pause menu and dialogs.
+type OverlayScene struct {
+	BaseScene
+}
+
+func (o *OverlayScene) IsOverlay() bool {
+	return true
+}
+
+// drawPanel dims everything drawn so far and draws the panel an overlay
+// puts its contents on.
+func drawPanel(screen *ui.ScaledScreen, x, y, w, h float64) {
+	screen.DrawRect(0, 0, 960, 720, color.RGBA{0, 0, 0, 140})
+	screen.DrawRect(x, y, w, h, color.RGBA{230, 228, 213, 255})
+	screen.DrawUnfilledRect(x, y, w, h, 4, color.RGBA{50, 60, 55, 255})
+}
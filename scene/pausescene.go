@@ -0,0 +1,53 @@
+package scene
+
+import (
+	"image/color"
+
+	"github.com/prizelobby/union-gridder/ui"
+)
+
+const (
+	PAUSE_RESUME = iota
+	PAUSE_NEW_GAME
+	PAUSE_MAIN_MENU
+)
+
+// PauseScene is the menu opened over a game with Escape or Start.
+type PauseScene struct {
+	OverlayScene
+	GameScene *GameScene
+	Menu      *ui.Menu
+}
+
+func NewPauseScene(gameScene *GameScene) *PauseScene {
+	return &PauseScene{
+		GameScene: gameScene,
+		Menu:      ui.NewMenu(960/2-150, 250, 300, 56, 20, "Resume", "New Game", "Main Menu"),
+	}
+}
+
+func (p *PauseScene) OnEnter() {
+	p.Menu.Selected = PAUSE_RESUME
+}
+
+func (p *PauseScene) Update() {
+	if isBackPressed() || isPausePressed() {
+		p.SceneManager.PopScene()
+		return
+	}
+	switch p.Menu.Update() {
+	case PAUSE_RESUME:
+		p.SceneManager.PopScene()
+	case PAUSE_NEW_GAME:
+		p.SceneManager.PopScene()
+		p.GameScene.ConfirmNewGame()
+	case PAUSE_MAIN_MENU:
+		p.SceneManager.SwitchToScene("menu")
+	}
+}
+
+func (p *PauseScene) Draw(screen *ui.ScaledScreen) {
+	drawPanel(screen, 960/2-200, 140, 400, 360)
+	screen.DrawTextCenteredAt("Paused", 48, 960/2, 200, color.Black)
+	p.Menu.Draw(screen)
+}
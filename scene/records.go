@@ -0,0 +1,104 @@
+package scene
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/prizelobby/union-gridder/core"
+	"github.com/prizelobby/union-gridder/storage"
+)
+
+const DAILY_LOG_FILE = "daily.json"
+const SAVE_FILE = "save.json"
+const STATS_FILE = "stats.json"
+
+// AUTOSAVE_INTERVAL is how often the game is saved while the player thinks,
+// so the clock isn't lost if the game is closed without warning.
+const AUTOSAVE_INTERVAL = 10 * time.Second
+
+func loadDailyLog() *core.DailyLog {
+	l := core.NewDailyLog()
+	data, err := storage.Load(DAILY_LOG_FILE)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Println("error loading daily log: " + err.Error())
+		}
+		return l
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		log.Println("error reading daily log: " + err.Error())
+		return core.NewDailyLog()
+	}
+	return l
+}
+
+func saveDailyLog(l *core.DailyLog) {
+	data, err := json.Marshal(l)
+	if err == nil {
+		err = storage.Save(DAILY_LOG_FILE, data)
+	}
+	if err != nil {
+		log.Println("error saving daily log: " + err.Error())
+	}
+}
+
+func loadStats() *core.Stats {
+	stats := core.NewStats()
+	data, err := storage.Load(STATS_FILE)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Println("error loading stats: " + err.Error())
+		}
+		return stats
+	}
+	if err := json.Unmarshal(data, stats); err != nil {
+		log.Println("error reading stats: " + err.Error())
+		return core.NewStats()
+	}
+	return stats
+}
+
+func saveStats(stats *core.Stats) {
+	data, err := json.Marshal(stats)
+	if err == nil {
+		err = storage.Save(STATS_FILE, data)
+	}
+	if err != nil {
+		log.Println("error saving stats: " + err.Error())
+	}
+}
+
+// loadSavedGame restores the game saved by saveGame into g. It returns false
+// if there is no unsolved game to continue.
+func loadSavedGame(g *core.Game) bool {
+	data, err := storage.Load(SAVE_FILE)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Println("error loading saved game: " + err.Error())
+		}
+		return false
+	}
+	if err := core.UnmarshalSave(g, data); err != nil {
+		log.Println("error reading saved game: " + err.Error())
+		return false
+	}
+	return !g.Solved
+}
+
+func saveGame(g *core.Game) {
+	data, err := core.MarshalSave(g)
+	if err == nil {
+		err = storage.Save(SAVE_FILE, data)
+	}
+	if err != nil {
+		log.Println("error saving game: " + err.Error())
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	seconds := int(d.Seconds())
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -3,25 +3,52 @@ package scene
 import (
 	"errors"
 
+	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/prizelobby/union-gridder/ui"
 )
 
 type Scene interface {
 	Update()
 	Draw(screen *ui.ScaledScreen)
-	OnSwitch()
+	// OnEnter is called when the scene is added to the stack and OnExit when
+	// it is removed.
+	OnEnter()
+	OnExit()
+	// OnPause is called when another scene is pushed on top of this one and
+	// OnResume when that scene is popped again.
+	OnPause()
+	OnResume()
 	SetSceneManager(sm *SceneManager)
 }
 
+// Overlay is implemented by scenes that are drawn on top of the scene below
+// them, such as dialogs, instead of covering the whole screen.
+type Overlay interface {
+	IsOverlay() bool
+}
+
+// SceneManager holds a stack of scenes. Only the scene on top is updated, so
+// an overlay blocks input to the scenes under it.
 type SceneManager struct {
-	CurrentScene Scene
-	SceneDict    map[string]Scene
+	SceneDict map[string]Scene
+	// Transition is played by SwitchToScene.
+	Transition Transition
+	// Quit is set by a scene when the player asks to close the game.
+	Quit  bool
+	stack []Scene
+	// outgoing is the stack being switched away from, drawn until the
+	// transition finishes. Nothing is updated during a transition.
+	outgoing   []Scene
+	transition Transition
+	progress   float64
+	fromImage  *ebiten.Image
+	toImage    *ebiten.Image
 }
 
 func NewSceneManager() *SceneManager {
 	return &SceneManager{
-		CurrentScene: &BaseScene{},
-		SceneDict:    make(map[string]Scene),
+		SceneDict:  make(map[string]Scene),
+		Transition: DEFAULT_TRANSITION,
 	}
 }
 
@@ -30,19 +57,142 @@ func (s *SceneManager) AddScene(name string, scene Scene) {
 	s.SceneDict[name] = scene
 }
 
-func (s *SceneManager) SwitchToScene(name string) error {
-	if nextScene, ok := s.SceneDict[name]; ok {
-		s.CurrentScene.OnSwitch()
-		s.CurrentScene = nextScene
+// CurrentScene returns the scene on top of the stack, or nil.
+func (s *SceneManager) CurrentScene() Scene {
+	if len(s.stack) == 0 {
 		return nil
 	}
-	return errors.New("Scene not found in dict")
+	return s.stack[len(s.stack)-1]
+}
+
+// SwitchToScene exits every scene on the stack and replaces them with the
+// named scene, playing the default transition.
+func (s *SceneManager) SwitchToScene(name string) error {
+	return s.SwitchToSceneWith(name, s.Transition)
+}
+
+func (s *SceneManager) SwitchToSceneWith(name string, transition Transition) error {
+	nextScene, ok := s.SceneDict[name]
+	if !ok {
+		return errors.New("Scene not found in dict")
+	}
+	outgoing := s.stack
+	s.stack = nil
+	for i := len(outgoing) - 1; i >= 0; i-- {
+		outgoing[i].OnExit()
+	}
+	s.stack = append(s.stack, nextScene)
+	nextScene.OnEnter()
+
+	if len(outgoing) > 0 && transition.Kind != NO_TRANSITION && transition.Duration > 0 {
+		s.outgoing = outgoing
+		s.transition = transition
+		s.progress = 0
+	}
+	return nil
+}
+
+// Close exits every scene on the stack, giving them a chance to save before
+// the game closes.
+func (s *SceneManager) Close() {
+	for len(s.stack) > 0 {
+		s.pop().OnExit()
+	}
+}
+
+// InTransition reports whether a transition between scenes is playing.
+func (s *SceneManager) InTransition() bool {
+	return s.outgoing != nil
+}
+
+// PushScene pauses the current scene and puts the named scene on top of it.
+func (s *SceneManager) PushScene(name string) error {
+	nextScene, ok := s.SceneDict[name]
+	if !ok {
+		return errors.New("Scene not found in dict")
+	}
+	return s.Push(nextScene)
+}
+
+// Push is like PushScene for a scene that isn't in the dict, such as a one-off
+// dialog.
+func (s *SceneManager) Push(scene Scene) error {
+	for _, sc := range s.stack {
+		if sc == scene {
+			return errors.New("Scene is already on the stack")
+		}
+	}
+	scene.SetSceneManager(s)
+	if top := s.CurrentScene(); top != nil {
+		top.OnPause()
+	}
+	s.stack = append(s.stack, scene)
+	scene.OnEnter()
+	return nil
+}
+
+// PopScene exits the current scene and resumes the one under it.
+func (s *SceneManager) PopScene() {
+	if len(s.stack) == 0 {
+		return
+	}
+	s.pop().OnExit()
+	if top := s.CurrentScene(); top != nil {
+		top.OnResume()
+	}
+}
+
+func (s *SceneManager) pop() Scene {
+	top := s.stack[len(s.stack)-1]
+	s.stack = s.stack[:len(s.stack)-1]
+	return top
 }
 
 func (s *SceneManager) Update() {
-	s.CurrentScene.Update()
+	if s.InTransition() {
+		s.progress += 1 / s.transition.ticks()
+		if s.progress >= 1 {
+			s.outgoing = nil
+		}
+		return
+	}
+	if top := s.CurrentScene(); top != nil {
+		top.Update()
+	}
 }
 
 func (s *SceneManager) Draw(screen *ui.ScaledScreen) {
-	s.CurrentScene.Draw(screen)
+	if !s.InTransition() {
+		drawStack(screen, s.stack)
+		return
+	}
+
+	target := screen.Screen
+	if s.fromImage == nil {
+		s.fromImage = screen.NewImage(960, 720)
+		s.toImage = screen.NewImage(960, 720)
+	}
+	s.fromImage.Clear()
+	s.toImage.Clear()
+	screen.SetTarget(s.fromImage)
+	drawStack(screen, s.outgoing)
+	screen.SetTarget(s.toImage)
+	drawStack(screen, s.stack)
+	screen.SetTarget(target)
+	s.transition.draw(screen, s.fromImage, s.toImage, min(s.progress, 1))
+}
+
+// drawStack draws the top scene of stack, and the scenes under it for as long
+// as the scenes on top are overlays.
+func drawStack(screen *ui.ScaledScreen, stack []Scene) {
+	bottom := len(stack) - 1
+	for bottom > 0 {
+		if o, ok := stack[bottom].(Overlay); !ok || !o.IsOverlay() {
+			break
+		}
+		bottom -= 1
+	}
+	for i := max(bottom, 0); i < len(stack); i++ {
+		stack[i].Draw(screen)
+	}
 }
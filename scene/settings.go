@@ -0,0 +1,75 @@
+package scene
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+
+	"github.com/prizelobby/union-gridder/core"
+	"github.com/prizelobby/union-gridder/ui"
+)
+
+var GRID_SIZES = [][2]int{{3, 3}, {2, 2}, {3, 4}, {4, 4}}
+
+// MAX_DIFFICULTIES holds the hardest difficulty offered for each of
+// GRID_SIZES. The generator can't make a 2x2 puzzle harder than Medium.
+var MAX_DIFFICULTIES = []core.Difficulty{core.EXPERT, core.MEDIUM, core.EXPERT, core.EXPERT}
+
+// Settings are the options used when starting a new random game.
+type Settings struct {
+	GridSize   int
+	Difficulty core.Difficulty
+}
+
+func (s *Settings) Rows() int {
+	return GRID_SIZES[s.GridSize][0]
+}
+
+func (s *Settings) Cols() int {
+	return GRID_SIZES[s.GridSize][1]
+}
+
+type SettingsScene struct {
+	BaseScene
+	Settings *Settings
+	Menu     *ui.Menu
+}
+
+func NewSettingsScene(settings *Settings) *SettingsScene {
+	s := &SettingsScene{
+		Settings: settings,
+		Menu:     ui.NewMenu(960/2-150, 260, 300, 56, 20, "", "", "Back"),
+	}
+	s.updateLabels()
+	return s
+}
+
+func (s *SettingsScene) updateLabels() {
+	s.Menu.Buttons[0].Label = fmt.Sprintf("Grid %dx%d", s.Settings.Rows(), s.Settings.Cols())
+	s.Menu.Buttons[1].Label = "Difficulty " + s.Settings.Difficulty.String()
+}
+
+func (s *SettingsScene) Update() {
+	switch s.Menu.Update() {
+	case 0:
+		s.Settings.GridSize = (s.Settings.GridSize + 1) % len(GRID_SIZES)
+		s.Settings.Difficulty = min(s.Settings.Difficulty, MAX_DIFFICULTIES[s.Settings.GridSize])
+	case 1:
+		difficulties := core.DIFFICULTIES[:slices.Index(core.DIFFICULTIES, MAX_DIFFICULTIES[s.Settings.GridSize])+1]
+		i := slices.Index(difficulties, s.Settings.Difficulty)
+		s.Settings.Difficulty = difficulties[(i+1)%len(difficulties)]
+	case 2:
+		s.SceneManager.SwitchToSceneWith("menu", SLIDE_OUT)
+	}
+	if isBackPressed() {
+		s.SceneManager.SwitchToSceneWith("menu", SLIDE_OUT)
+	}
+	s.updateLabels()
+}
+
+func (s *SettingsScene) Draw(screen *ui.ScaledScreen) {
+	screen.Screen.Fill(color.RGBA{230, 228, 213, 255})
+	screen.DrawTextCenteredAt("Settings", 64, 960/2, 120, color.Black)
+	screen.DrawTextCenteredAt("Used for the next new game", 24, 960/2, 200, color.RGBA{90, 90, 90, 255})
+	s.Menu.Draw(screen)
+}
@@ -0,0 +1,97 @@
+package scene
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/prizelobby/union-gridder/core"
+	"github.com/prizelobby/union-gridder/ui"
+)
+
+type StatsScene struct {
+	BaseScene
+	Stats *core.Stats
+	Back  *ui.Menu
+	// summary and the charts are worked out when the scene is entered
+	summary      core.StatsSummary
+	histogram    []int
+	difficulties []int
+}
+
+func NewStatsScene(stats *core.Stats) *StatsScene {
+	return &StatsScene{
+		Stats: stats,
+		Back:  ui.NewMenu(960/2-100, 650, 200, 50, 0, "Back"),
+	}
+}
+
+func (s *StatsScene) OnEnter() {
+	s.summary = s.Stats.Summary(time.Now())
+	s.histogram = s.Stats.TimeHistogram()
+	s.difficulties = s.Stats.DifficultyCounts()
+}
+
+func (s *StatsScene) Update() {
+	if s.Back.Update() == 0 || isBackPressed() {
+		s.SceneManager.SwitchToSceneWith("menu", SLIDE_OUT)
+	}
+}
+
+func (s *StatsScene) Draw(screen *ui.ScaledScreen) {
+	screen.Screen.Fill(color.RGBA{230, 228, 213, 255})
+	screen.DrawTextCenteredAt("Statistics", 64, 960/2, 70, color.Black)
+
+	sum := s.summary
+	lines := []string{
+		fmt.Sprintf("Played %d", sum.Played),
+		fmt.Sprintf("Solved %d", sum.Won),
+		fmt.Sprintf("Win rate %.0f%%", sum.WinRate*100),
+		"Average time " + formatDuration(sum.AverageTime),
+		"Best time " + formatDuration(sum.BestTime),
+		fmt.Sprintf("Best score %d", sum.BestScore),
+		fmt.Sprintf("Daily streak %d", sum.CurrentStreak),
+		fmt.Sprintf("Longest streak %d", sum.LongestStreak),
+	}
+	for i, l := range lines {
+		screen.DrawText(l, 28, 60, 150+i*55, color.Black)
+	}
+
+	timeLabels := make([]string, len(s.histogram))
+	for i := range timeLabels {
+		timeLabels[i] = formatDuration(time.Duration(i) * core.HISTOGRAM_WIDTH)
+	}
+	timeLabels[len(timeLabels)-1] += "+"
+	drawBarChart(screen, "Solve times", 440, 150, 460, 200, s.histogram, timeLabels)
+
+	difficultyLabels := make([]string, len(s.difficulties))
+	for i := range difficultyLabels {
+		difficultyLabels[i] = core.DIFFICULTIES[i+1].String()
+	}
+	drawBarChart(screen, "Solved by difficulty", 440, 420, 460, 180, s.difficulties, difficultyLabels)
+
+	s.Back.Draw(screen)
+}
+
+// drawBarChart draws values as bars scaled to the tallest one, with the
+// labels under them and the counts on top.
+func drawBarChart(screen *ui.ScaledScreen, title string, x, y, w, h float64, values []int, labels []string) {
+	screen.DrawText(title, 24, int(x), int(y)-10, color.Black)
+	top := 1
+	for _, v := range values {
+		top = max(top, v)
+	}
+	chartTop := y + 30
+	chartHeight := h - 60
+	pitch := w / float64(len(values))
+	for i, v := range values {
+		barHeight := chartHeight * float64(v) / float64(top)
+		bx := x + float64(i)*pitch + 4
+		screen.DrawRect(bx, chartTop+chartHeight-barHeight, pitch-8, barHeight, color.RGBA{124, 194, 154, 255})
+		if v > 0 {
+			screen.DrawTextCenteredAt(fmt.Sprint(v), 16, int(bx+(pitch-8)/2), int(chartTop+chartHeight-barHeight-12), color.Black)
+		}
+		screen.DrawTextCenteredAt(labels[i], 16, int(bx+(pitch-8)/2), int(chartTop+chartHeight+16), color.RGBA{90, 90, 90, 255})
+	}
+	screen.DrawRect(x, chartTop+chartHeight, w, 2, color.RGBA{50, 60, 55, 255})
+}
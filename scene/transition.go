@@ -0,0 +1,76 @@
+package scene
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/prizelobby/union-gridder/res"
+	"github.com/prizelobby/union-gridder/ui"
+)
+
+type TransitionKind int
+
+const (
+	NO_TRANSITION TransitionKind = iota
+	// FADE cross-fades from the old scene to the new one.
+	FADE
+	// SLIDE_LEFT pushes the old scene out to the left, SLIDE_RIGHT to the right.
+	SLIDE_LEFT
+	SLIDE_RIGHT
+	// WIPE sweeps a soft edge across the screen using the wipe shader.
+	WIPE
+)
+
+type Transition struct {
+	Kind     TransitionKind
+	Duration time.Duration
+}
+
+var DEFAULT_TRANSITION = Transition{Kind: FADE, Duration: 250 * time.Millisecond}
+
+// SLIDE_IN and SLIDE_OUT are used going into a submenu and back out of it,
+// WIPE_IN when starting to play.
+var SLIDE_IN = Transition{Kind: SLIDE_LEFT, Duration: 350 * time.Millisecond}
+var SLIDE_OUT = Transition{Kind: SLIDE_RIGHT, Duration: 350 * time.Millisecond}
+var WIPE_IN = Transition{Kind: WIPE, Duration: 600 * time.Millisecond}
+
+func (t Transition) ticks() float64 {
+	return t.Duration.Seconds() * float64(ebiten.TPS())
+}
+
+// draw composes the images of the old and new scenes, progress running from
+// 0 to 1 over the transition.
+func (t Transition) draw(screen *ui.ScaledScreen, from, to *ebiten.Image, progress float64) {
+	// ease in and out
+	p := progress * progress * (3 - 2*progress)
+	w := float64(from.Bounds().Dx())
+
+	switch t.Kind {
+	case SLIDE_LEFT, SLIDE_RIGHT:
+		dir := -1.0
+		if t.Kind == SLIDE_RIGHT {
+			dir = 1
+		}
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Translate(dir*p*w, 0)
+		screen.Screen.DrawImage(from, opts)
+		opts = &ebiten.DrawImageOptions{}
+		opts.GeoM.Translate(dir*(p-1)*w, 0)
+		screen.Screen.DrawImage(to, opts)
+		return
+	case WIPE:
+		if shader := res.GetShader("wipe"); shader != nil {
+			opts := &ebiten.DrawRectShaderOptions{}
+			opts.Images[0] = from
+			opts.Images[1] = to
+			opts.Uniforms = map[string]any{"Progress": float32(p)}
+			screen.DrawRectShader(960, 720, shader, opts)
+			return
+		}
+	}
+
+	screen.Screen.DrawImage(from, &ebiten.DrawImageOptions{})
+	opts := &ebiten.DrawImageOptions{}
+	opts.ColorScale.ScaleAlpha(float32(p))
+	screen.Screen.DrawImage(to, opts)
+}
@@ -0,0 +1,292 @@
+// Package solver finds every arrangement of a set list that satisfies the row
+// and column unions of a union-grid puzzle. It has no dependency on ebiten so it
+// can be used from the game, tests and command-line tools alike.
+package solver
+
+import (
+	"slices"
+
+	"github.com/prizelobby/union-gridder/util"
+)
+
+type Solver struct {
+	Rows int
+	Cols int
+	Sets []string
+	// Targets holds the row targets followed by the column targets.
+	Targets []string
+	// Limit stops the search after this many solutions, 0 means no limit.
+	Limit int
+	// Fixed optionally holds a set for each cell that solutions must keep
+	// there. Empty strings leave a cell free.
+	Fixed []string
+	// MaxNodes stops the search once it has placed this many sets, 0 means no
+	// limit. Exceeded is then set and Solutions may be missing some.
+	MaxNodes int
+
+	Solutions  [][]string
+	Nodes      int
+	Backtracks int
+	Exceeded   bool
+
+	used    []bool
+	fixed   []int
+	slots   []string
+	masks   []util.LetterSet
+	targets []util.LetterSet
+	filled  []util.LetterSet
+	fits    [][]int
+	seen    []int
+	stamp   int
+	match   []int
+	need    []util.LetterSet
+}
+
+type Result struct {
+	Solutions  [][]string
+	Count      int
+	Nodes      int
+	Backtracks int
+}
+
+func NewSolver(rows, cols int, sets []string, targets []string) *Solver {
+	return &Solver{
+		Rows:    rows,
+		Cols:    cols,
+		Sets:    sets,
+		Targets: targets,
+	}
+}
+
+// Solve returns every solution of the puzzle along with the search statistics.
+func Solve(rows, cols int, sets []string, targets []string) Result {
+	s := NewSolver(rows, cols, sets, targets)
+	s.Run()
+	return s.Result()
+}
+
+// Count returns the number of solutions of the puzzle, stopping at limit.
+func Count(rows, cols int, sets []string, targets []string, limit int) int {
+	s := NewSolver(rows, cols, sets, targets)
+	s.Limit = limit
+	return s.Run()
+}
+
+// Run searches for solutions and returns how many were found.
+func (s *Solver) Run() int {
+	s.Solutions = nil
+	s.Nodes = 0
+	s.Backtracks = 0
+	s.Exceeded = false
+	if s.Rows <= 0 || s.Cols <= 0 || len(s.Targets) != s.Rows+s.Cols || len(s.Sets) < s.Rows*s.Cols {
+		return 0
+	}
+	s.used = make([]bool, len(s.Sets))
+	s.fixed = make([]int, s.Rows*s.Cols)
+	s.slots = make([]string, s.Rows*s.Cols)
+	s.filled = make([]util.LetterSet, s.Rows*s.Cols)
+	alphabet, err := util.AlphabetOf(s.Targets...)
+	if err != nil {
+		return 0
+	}
+	s.masks = make([]util.LetterSet, len(s.Sets))
+	for i, set := range s.Sets {
+		s.masks[i] = alphabet.Set(set)
+		// a set with a letter outside every target can never be placed
+		s.used[i] = !alphabet.Covers(set)
+	}
+	s.targets = make([]util.LetterSet, len(s.Targets))
+	for i, t := range s.Targets {
+		s.targets[i] = alphabet.Set(t)
+	}
+	for cell := range s.fixed {
+		s.fixed[cell] = -1
+		if cell >= len(s.Fixed) || s.Fixed[cell] == "" {
+			continue
+		}
+		i := slices.Index(s.Sets, s.Fixed[cell])
+		if i == -1 || s.used[i] {
+			return 0
+		}
+		// fixed sets are only available in their own cell
+		s.fixed[cell] = i
+		s.used[i] = true
+	}
+	// the sets that can go in each cell, ignoring which ones are used
+	s.fits = make([][]int, len(s.slots))
+	s.seen = make([]int, len(s.Sets))
+	s.match = make([]int, len(s.Sets))
+	s.need = make([]util.LetterSet, len(s.slots))
+	for cell := range s.fits {
+		rowTarget, colTarget := s.targets[cell/s.Cols], s.targets[s.Rows+cell%s.Cols]
+		for i, set := range s.masks {
+			if s.fixed[cell] != -1 && s.fixed[cell] != i {
+				continue
+			}
+			if set.IsSubsetOf(rowTarget) && set.IsSubsetOf(colTarget) {
+				s.fits[cell] = append(s.fits[cell], i)
+			}
+		}
+	}
+	s.place(0)
+	return len(s.Solutions)
+}
+
+func (s *Solver) Result() Result {
+	return Result{
+		Solutions:  s.Solutions,
+		Count:      len(s.Solutions),
+		Nodes:      s.Nodes,
+		Backtracks: s.Backtracks,
+	}
+}
+
+// place fills the cell at index and everything after it in row-major order,
+// returning true once the search should stop.
+func (s *Solver) place(index int) bool {
+	if index == len(s.slots) {
+		solution := make([]string, len(s.slots))
+		copy(solution, s.slots)
+		s.Solutions = append(s.Solutions, solution)
+		return s.Limit > 0 && len(s.Solutions) >= s.Limit
+	}
+
+	row, col := index/s.Cols, index%s.Cols
+	rowTarget, colTarget := s.targets[row], s.targets[s.Rows+col]
+	found := false
+	// fits already leaves out sets with a letter outside either target, which
+	// would make the row or column dead
+	for _, i := range s.fits[index] {
+		if s.used[i] && s.fixed[index] != i {
+			continue
+		}
+		set := s.masks[i]
+		s.filled[index] = set
+		if col == s.Cols-1 && s.rowUnion(row) != rowTarget {
+			continue
+		}
+		if row == s.Rows-1 && s.columnUnion(col) != colTarget {
+			continue
+		}
+		wasUsed := s.used[i]
+		s.used[i] = true
+		if !s.canFinish(index) {
+			s.used[i] = wasUsed
+			continue
+		}
+
+		s.Nodes += 1
+		if s.MaxNodes > 0 && s.Nodes > s.MaxNodes {
+			s.Exceeded = true
+			return true
+		}
+		s.slots[index] = s.Sets[i]
+		done := s.place(index + 1)
+		s.used[i] = wasUsed
+		if done {
+			return true
+		}
+		found = true
+	}
+	s.filled[index] = 0
+	s.slots[index] = ""
+	if !found {
+		s.Backtracks += 1
+	}
+	return false
+}
+
+// canFinish reports whether, with the cells up to index filled, every line
+// can still be finished: the target letters it lacks must come from the sets
+// that fit its empty cells, and every empty cell needs a set of its own.
+func (s *Solver) canFinish(index int) bool {
+	for cell := index + 1; cell < len(s.slots); cell++ {
+		s.need[cell] = 0
+	}
+	for row := index / s.Cols; row < s.Rows; row++ {
+		if !s.lineCanFinish(row, row*s.Cols, 1, s.Cols, index) {
+			return false
+		}
+	}
+	for col := range s.Cols {
+		if !s.lineCanFinish(s.Rows+col, col, s.Cols, s.Rows, index) {
+			return false
+		}
+	}
+	// the lines are checked one at a time, so also make sure they aren't
+	// relying on the same few sets
+	for i := range s.match {
+		s.match[i] = -1
+	}
+	for cell := index + 1; cell < len(s.slots); cell++ {
+		s.stamp += 1
+		if !s.assign(cell) {
+			return false
+		}
+	}
+	return true
+}
+
+// lineCanFinish checks the line whose cells start at first and are step apart.
+// When one cell is left, it records the letters that cell must bring in need.
+func (s *Solver) lineCanFinish(line, first, step, length, index int) bool {
+	var have util.LetterSet
+	for k := range length {
+		if cell := first + k*step; cell <= index {
+			have |= s.filled[cell]
+		}
+	}
+	missing := s.targets[line] &^ have
+
+	s.stamp += 1
+	var can util.LetterSet
+	empty, available, last := 0, 0, 0
+	for k := range length {
+		cell := first + k*step
+		if cell <= index {
+			continue
+		}
+		empty += 1
+		last = cell
+		for _, i := range s.fits[cell] {
+			if s.seen[i] == s.stamp || (s.used[i] && s.fixed[cell] != i) {
+				continue
+			}
+			s.seen[i] = s.stamp
+			available += 1
+			can |= s.masks[i]
+		}
+	}
+	if empty == 1 {
+		s.need[last] |= missing
+	}
+	return available >= empty && missing.IsSubsetOf(can)
+}
+
+// assign finds an unused set for cell, moving sets already given to other
+// empty cells around if needed.
+func (s *Solver) assign(cell int) bool {
+	for _, i := range s.fits[cell] {
+		if s.seen[i] == s.stamp || (s.used[i] && s.fixed[cell] != i) || !s.need[cell].IsSubsetOf(s.masks[i]) {
+			continue
+		}
+		s.seen[i] = s.stamp
+		if s.match[i] == -1 || s.assign(s.match[i]) {
+			s.match[i] = cell
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Solver) rowUnion(row int) util.LetterSet {
+	return util.LetterSetsUnion(s.filled[row*s.Cols : (row+1)*s.Cols]...)
+}
+
+func (s *Solver) columnUnion(col int) util.LetterSet {
+	var out util.LetterSet
+	for row := range s.Rows {
+		out |= s.filled[row*s.Cols+col]
+	}
+	return out
+}
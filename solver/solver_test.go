@@ -0,0 +1,242 @@
+package solver
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// union returns the distinct letters of sets in sorted order.
+func union(sets ...string) string {
+	letters := []rune(strings.Join(sets, ""))
+	slices.Sort(letters)
+	return string(slices.Compact(letters))
+}
+
+// targetsOf returns the row and column unions of a filled grid.
+func targetsOf(rows, cols int, grid []string) []string {
+	targets := make([]string, 0, rows+cols)
+	for r := range rows {
+		targets = append(targets, union(grid[r*cols:(r+1)*cols]...))
+	}
+	for c := range cols {
+		col := make([]string, 0, rows)
+		for r := range rows {
+			col = append(col, grid[r*cols+c])
+		}
+		targets = append(targets, union(col...))
+	}
+	return targets
+}
+
+// bruteForce tries every ordered choice of rows*cols distinct sets and keeps
+// the ones whose unions match the targets.
+func bruteForce(rows, cols int, sets []string, targets []string) [][]string {
+	want := make([]string, len(targets))
+	for i, t := range targets {
+		want[i] = union(t)
+	}
+	var solutions [][]string
+	grid := make([]string, rows*cols)
+	used := make([]bool, len(sets))
+	var fill func(cell int)
+	fill = func(cell int) {
+		if cell == len(grid) {
+			if slices.Equal(targetsOf(rows, cols, grid), want) {
+				solutions = append(solutions, slices.Clone(grid))
+			}
+			return
+		}
+		for i, s := range sets {
+			if used[i] {
+				continue
+			}
+			used[i] = true
+			grid[cell] = s
+			fill(cell + 1)
+			used[i] = false
+		}
+	}
+	fill(0)
+	return solutions
+}
+
+// randomPuzzle fills a grid with distinct random sets drawn from letters and
+// returns its sets and targets. With few letters, alternate solutions are
+// common.
+func randomPuzzle(r *rand.Rand, letters string, rows, cols, extra int) ([]string, []string) {
+	seen := map[string]bool{}
+	var sets []string
+	for len(sets) < rows*cols+extra {
+		var b strings.Builder
+		for _, l := range letters {
+			if r.IntN(3) == 0 {
+				b.WriteRune(l)
+			}
+		}
+		s := b.String()
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		sets = append(sets, s)
+	}
+	return sets, targetsOf(rows, cols, sets[:rows*cols])
+}
+
+func sortedSolutions(solutions [][]string) []string {
+	out := make([]string, len(solutions))
+	for i, s := range solutions {
+		out[i] = strings.Join(s, " ")
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	// rows, cols, extra sets and puzzles to try; 3x3 brute force is slow
+	sizes := [][4]int{{1, 1, 1, 20}, {1, 3, 2, 20}, {2, 2, 2, 20}, {2, 3, 1, 20}, {3, 2, 1, 20}, {3, 3, 0, 2}}
+	for _, size := range sizes {
+		rows, cols, extra := size[0], size[1], size[2]
+		for range size[3] {
+			sets, targets := randomPuzzle(r, "ABCDE", rows, cols, extra)
+			want := bruteForce(rows, cols, sets, targets)
+			if n := Count(rows, cols, sets, targets, 0); n != len(want) {
+				t.Fatalf("%dx%d %v %v: Count = %d, brute force found %d", rows, cols, sets, targets, n, len(want))
+			}
+			got := Solve(rows, cols, sets, targets)
+			if !slices.Equal(sortedSolutions(got.Solutions), sortedSolutions(want)) {
+				t.Fatalf("%dx%d %v %v: solutions %v, want %v", rows, cols, sets, targets, got.Solutions, want)
+			}
+		}
+	}
+}
+
+// With few letters almost every set fits almost everywhere, so only the
+// look-ahead on missing letters keeps the search from being brute force.
+func TestSmallAlphabet(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	for i := range 2 {
+		sets, targets := randomPuzzle(r, "ABCD", 3, 3, 0)
+		if i%2 == 1 {
+			// targets from another grid rarely have a solution, which makes
+			// the search go through everything
+			_, targets = randomPuzzle(r, "ABCD", 3, 3, 0)
+		}
+		want := bruteForce(3, 3, sets, targets)
+		got := Solve(3, 3, sets, targets)
+		if !slices.Equal(sortedSolutions(got.Solutions), sortedSolutions(want)) {
+			t.Fatalf("%v %v: solutions %v, want %v", sets, targets, got.Solutions, want)
+		}
+	}
+
+	nodes := 0
+	for range 10 {
+		sets, _ := randomPuzzle(r, "ABCDE", 4, 4, 0)
+		_, targets := randomPuzzle(r, "ABCDE", 4, 4, 0)
+		s := NewSolver(4, 4, sets, targets)
+		s.Limit = 2
+		s.Run()
+		nodes += s.Nodes
+	}
+	// without the look-ahead these take many millions of nodes
+	if nodes > 250_000 {
+		t.Errorf("4x4 grids over 5 letters took %d nodes", nodes)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	// Every arrangement of these sets is a solution.
+	sets := []string{"AB", "ABC", "AC", "BC"}
+	targets := []string{"ABC", "ABC", "ABC", "ABC"}
+	all := len(bruteForce(2, 2, sets, targets))
+	if all < 3 {
+		t.Fatalf("test puzzle has %d solutions, want several", all)
+	}
+	for _, limit := range []int{1, 2, all, all + 5} {
+		want := min(limit, all)
+		if n := Count(2, 2, sets, targets, limit); n != want {
+			t.Errorf("limit %d: Count = %d, want %d", limit, n, want)
+		}
+	}
+	if n := Count(2, 2, sets, targets, 0); n != all {
+		t.Errorf("no limit: Count = %d, want %d", n, all)
+	}
+}
+
+func TestFixed(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for range 20 {
+		sets, targets := randomPuzzle(r, "ABCDE", 2, 3, 1)
+		all := bruteForce(2, 3, sets, targets)
+		cell := r.IntN(6)
+
+		s := NewSolver(2, 3, sets, targets)
+		s.Fixed = make([]string, 6)
+		s.Fixed[cell] = sets[cell]
+		want := 0
+		for _, solution := range all {
+			if solution[cell] == sets[cell] {
+				want += 1
+			}
+		}
+		if n := s.Run(); n != want {
+			t.Fatalf("%v %v with %s fixed at %d: %d solutions, want %d", sets, targets, sets[cell], cell, n, want)
+		}
+		for _, solution := range s.Solutions {
+			if solution[cell] != sets[cell] {
+				t.Fatalf("solution %v moved the fixed set %s", solution, sets[cell])
+			}
+		}
+	}
+
+	s := NewSolver(1, 2, []string{"A", "B"}, []string{"AB", "A", "B"})
+	s.Fixed = []string{"C"}
+	if n := s.Run(); n != 0 {
+		t.Errorf("fixing an unknown set found %d solutions", n)
+	}
+	s.Fixed = []string{"B"}
+	if n := s.Run(); n != 0 {
+		t.Errorf("fixing a set in the wrong cell found %d solutions", n)
+	}
+}
+
+func TestStatistics(t *testing.T) {
+	// Only "A B" works: each placement succeeds and nothing backtracks.
+	got := Solve(1, 2, []string{"A", "B"}, []string{"AB", "A", "B"})
+	if got.Count != 1 || got.Nodes != 2 || got.Backtracks != 0 {
+		t.Errorf("direct solve: %d solutions, %d nodes, %d backtracks, want 1, 2, 0", got.Count, got.Nodes, got.Backtracks)
+	}
+
+	// "A" fits the first cell but nothing left can give the row its B, so it
+	// is never placed.
+	got = Solve(1, 2, []string{"A", "B"}, []string{"AB", "A", "C"})
+	if got.Count != 0 || got.Nodes != 0 || got.Backtracks != 1 {
+		t.Errorf("dead end: %d solutions, %d nodes, %d backtracks, want 0, 0, 1", got.Count, got.Nodes, got.Backtracks)
+	}
+
+	// Running again starts the statistics over.
+	s := NewSolver(1, 2, []string{"A", "B"}, []string{"AB", "A", "B"})
+	s.Run()
+	s.Run()
+	if len(s.Solutions) != 1 || s.Nodes != 2 || s.Backtracks != 0 {
+		t.Errorf("second run: %d solutions, %d nodes, %d backtracks, want 1, 2, 0", len(s.Solutions), s.Nodes, s.Backtracks)
+	}
+}
+
+func TestMaxNodes(t *testing.T) {
+	sets := []string{"AB", "ABC", "AC", "BC"}
+	targets := []string{"ABC", "ABC", "ABC", "ABC"}
+	s := NewSolver(2, 2, sets, targets)
+	s.MaxNodes = 5
+	s.Run()
+	if !s.Exceeded || s.Nodes != 6 || len(s.Solutions) > 1 {
+		t.Errorf("budget of 5: exceeded %v after %d nodes and %d solutions", s.Exceeded, s.Nodes, len(s.Solutions))
+	}
+	s.MaxNodes = 0
+	if n := s.Run(); s.Exceeded || n != 24 {
+		t.Errorf("no budget: exceeded %v with %d solutions, want 24", s.Exceeded, n)
+	}
+}
@@ -0,0 +1,11 @@
+// Package storage keeps small named blobs of data for the player between
+// runs: files in the user config directory on desktop and localStorage in the
+// browser.
+package storage
+
+import "errors"
+
+const APP_NAME = "gridder-union"
+
+// ErrNotFound is returned by Load when nothing has been saved under a name.
+var ErrNotFound = errors.New("no saved data")
@@ -0,0 +1,44 @@
+//go:build js
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"syscall/js"
+)
+
+func localStorage() (js.Value, error) {
+	s := js.Global().Get("localStorage")
+	if s.IsUndefined() || s.IsNull() {
+		return js.Value{}, errors.New("localStorage is not available")
+	}
+	return s, nil
+}
+
+func Load(name string) ([]byte, error) {
+	s, err := localStorage()
+	if err != nil {
+		return nil, err
+	}
+	v := s.Call("getItem", APP_NAME+"/"+name)
+	if v.IsNull() {
+		return nil, ErrNotFound
+	}
+	return []byte(v.String()), nil
+}
+
+func Save(name string, data []byte) (err error) {
+	// setItem throws when the storage quota is used up
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("saving %s: %v", name, r)
+		}
+	}()
+	s, err := localStorage()
+	if err != nil {
+		return err
+	}
+	s.Call("setItem", APP_NAME+"/"+name, string(data))
+	return nil
+}
@@ -0,0 +1,38 @@
+package ui
+
+import "image/color"
+
+type Button struct {
+	X, Y, W, H float64
+	Label      string
+	Focused    bool
+	Disabled   bool
+}
+
+func NewButton(x, y, w, h float64, label string) *Button {
+	return &Button{
+		X:     x,
+		Y:     y,
+		W:     w,
+		H:     h,
+		Label: label,
+	}
+}
+
+func (b *Button) Contains(x, y float64) bool {
+	return x >= b.X && x < b.X+b.W && y >= b.Y && y < b.Y+b.H
+}
+
+func (b *Button) Draw(screen *ScaledScreen) {
+	fill := color.RGBA{255, 255, 255, 255}
+	if b.Focused && !b.Disabled {
+		fill = color.RGBA{124, 194, 154, 255}
+	}
+	textColor := color.Color(color.Black)
+	if b.Disabled {
+		textColor = color.RGBA{160, 160, 160, 255}
+	}
+	screen.DrawRect(b.X, b.Y, b.W, b.H, fill)
+	screen.DrawUnfilledRect(b.X, b.Y, b.W, b.H, 4, color.RGBA{50, 60, 55, 255})
+	screen.DrawTextCenteredAt(b.Label, 24, int(b.X+b.W/2), int(b.Y+b.H/2), textColor)
+}
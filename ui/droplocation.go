@@ -11,6 +11,12 @@ type DropLocation struct {
 func (d *DropLocation) Contains(x, y float64) bool {
 	return x >= d.X && x < d.X+d.W && y >= d.Y && y < d.Y+d.H
 }
+
+// SpritePosition returns where a set sprite sits when centered in the location.
+func (d *DropLocation) SpritePosition() (float64, float64) {
+	return d.X + d.W/2 - SetSpriteWidth/2, d.Y + d.H/2 - SetSpriteHeight/2
+}
+
 func (d *DropLocation) Draw(screen *ScaledScreen, extras []color.Color) {
 	screen.DrawRect(float64(d.X), float64(d.Y), float64(d.W), float64(d.H), color.RGBA{124, 194, 154, 255})
 	screen.DrawUnfilledRect(float64(d.X), float64(d.Y), float64(d.W), float64(d.H), 10, color.RGBA{101, 153, 145, 255})
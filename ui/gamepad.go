@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// STICK_THRESHOLD is how far a stick has to be pushed to count as a direction.
+const STICK_THRESHOLD = 0.5
+
+// A held direction repeats after REPEAT_DELAY ticks, then every
+// REPEAT_INTERVAL ticks.
+const REPEAT_DELAY = 24
+const REPEAT_INTERVAL = 6
+
+// gamepad merges every connected gamepad with the standard layout, so any
+// of them can be used.
+var gamepad struct {
+	ids    []ebiten.GamepadID
+	dx, dy int
+	ticks  int
+}
+
+// UpdateGamepads has to be called once per tick before the scenes update.
+func UpdateGamepads() {
+	gamepad.ids = ebiten.AppendGamepadIDs(gamepad.ids[:0])
+
+	dx, dy := 0, 0
+	for _, id := range gamepad.ids {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		x := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+		y := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+		switch {
+		case ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftLeft) || x < -STICK_THRESHOLD:
+			dx = -1
+		case ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftRight) || x > STICK_THRESHOLD:
+			dx = 1
+		}
+		switch {
+		case ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftTop) || y < -STICK_THRESHOLD:
+			dy = -1
+		case ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftBottom) || y > STICK_THRESHOLD:
+			dy = 1
+		}
+	}
+	// diagonals move vertically, which is what menus need
+	if dy != 0 {
+		dx = 0
+	}
+
+	if dx == 0 && dy == 0 {
+		gamepad.ticks = 0
+	} else if dx == gamepad.dx && dy == gamepad.dy {
+		gamepad.ticks += 1
+	} else {
+		gamepad.ticks = 1
+	}
+	gamepad.dx, gamepad.dy = dx, dy
+}
+
+// GamepadDirection returns the direction pushed on the D-pad or left stick
+// when it was just pushed or is repeating, and 0, 0 otherwise.
+func GamepadDirection() (int, int) {
+	t := gamepad.ticks
+	if t == 1 || (t > REPEAT_DELAY && (t-REPEAT_DELAY)%REPEAT_INTERVAL == 0) {
+		return gamepad.dx, gamepad.dy
+	}
+	return 0, 0
+}
+
+// IsGamepadButtonJustPressed reports whether button was just pressed on any
+// gamepad with the standard layout.
+func IsGamepadButtonJustPressed(button ebiten.StandardGamepadButton) bool {
+	for _, id := range gamepad.ids {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) && inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+			return true
+		}
+	}
+	return false
+}
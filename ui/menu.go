@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Menu is a vertical list of buttons that can be used with the mouse, touch,
+// the arrow keys and Enter, or a gamepad.
+type Menu struct {
+	Buttons  []*Button
+	Selected int
+}
+
+func NewMenu(x, y, w, h, spacing float64, labels ...string) *Menu {
+	m := &Menu{}
+	for i, l := range labels {
+		m.Buttons = append(m.Buttons, NewButton(x, y+float64(i)*(h+spacing), w, h, l))
+	}
+	return m
+}
+
+// Update returns the index of the button that was activated this update, or -1.
+func (m *Menu) Update() int {
+	_, dy := GamepadDirection()
+	if inpututil.IsKeyJustPressed(ebiten.KeyDown) || dy > 0 {
+		m.move(1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyUp) || dy < 0 {
+		m.move(-1)
+	}
+
+	activated := -1
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace) ||
+		IsGamepadButtonJustPressed(ebiten.StandardGamepadButtonRightBottom) {
+		activated = m.Selected
+	}
+	cursorX, cursorY := PointerPosition()
+	for i, b := range m.Buttons {
+		if b.Disabled || !b.Contains(cursorX, cursorY) {
+			continue
+		}
+		if IsPointerJustPressed() {
+			m.Selected = i
+			activated = i
+		}
+	}
+	if activated != -1 && m.Buttons[activated].Disabled {
+		activated = -1
+	}
+
+	m.updateFocus()
+	return activated
+}
+
+// move selects the next enabled button in direction d, wrapping around.
+func (m *Menu) move(d int) {
+	for range m.Buttons {
+		m.Selected = (m.Selected + d + len(m.Buttons)) % len(m.Buttons)
+		if !m.Buttons[m.Selected].Disabled {
+			return
+		}
+	}
+}
+
+func (m *Menu) updateFocus() {
+	if m.Buttons[m.Selected].Disabled {
+		m.move(1)
+	}
+	for i, b := range m.Buttons {
+		b.Focused = i == m.Selected
+	}
+}
+
+func (m *Menu) Draw(screen *ScaledScreen) {
+	for _, b := range m.Buttons {
+		b.Draw(screen)
+	}
+}
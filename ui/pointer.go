@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// The pointer is the mouse, or the first finger on a touch screen, so that
+// scenes handle clicks and taps the same way. UpdatePointer has to be called
+// once per tick before the scenes update.
+var pointer struct {
+	x, y         float64
+	touchID      ebiten.TouchID
+	touching     bool
+	justPressed  bool
+	justReleased bool
+}
+
+var touchIDs []ebiten.TouchID
+
+func UpdatePointer() {
+	pointer.justPressed = false
+	pointer.justReleased = false
+
+	if pointer.touching {
+		if inpututil.IsTouchJustReleased(pointer.touchID) {
+			// keep the last position so the release can be handled where it happened
+			pointer.touching = false
+			pointer.justReleased = true
+			return
+		}
+		pointer.x, pointer.y = adjustPosition(ebiten.TouchPosition(pointer.touchID))
+		return
+	}
+
+	touchIDs = inpututil.AppendJustPressedTouchIDs(touchIDs[:0])
+	if len(touchIDs) > 0 {
+		pointer.touchID = touchIDs[0]
+		pointer.touching = true
+		pointer.justPressed = true
+		pointer.x, pointer.y = adjustPosition(ebiten.TouchPosition(pointer.touchID))
+		return
+	}
+
+	pointer.x, pointer.y = AdjustedCursorPosition()
+	pointer.justPressed = inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	pointer.justReleased = inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft)
+}
+
+// PointerPosition returns the position of the pointer in game coordinates.
+func PointerPosition() (float64, float64) {
+	return pointer.x, pointer.y
+}
+
+func IsPointerJustPressed() bool {
+	return pointer.justPressed
+}
+
+func IsPointerJustReleased() bool {
+	return pointer.justReleased
+}
+
+func adjustPosition(x, y int) (float64, float64) {
+	return float64(x) / ebiten.Monitor().DeviceScaleFactor(), float64(y) / ebiten.Monitor().DeviceScaleFactor()
+}
@@ -76,14 +76,27 @@ func (s *ScaledScreen) DrawCircle(cx, cy, r float64, color color.Color) {
 	vector.DrawFilledCircle(s.Screen, xx, yy, rr, color, false)
 }
 
+// DrawRectShader draws a w by h rectangle, positioned by opts.GeoM in game
+// coordinates. The rectangle covers w*h scaled pixels, so source images have
+// to be the scaled size.
 func (s *ScaledScreen) DrawRectShader(w, h int, shader *ebiten.Shader, opts *ebiten.DrawRectShaderOptions) {
 	ww := int(float64(w) * s.scaleFactor)
 	hh := int(float64(h) * s.scaleFactor)
 
-	opts.GeoM.Scale(s.scaleFactor, s.scaleFactor)
+	var geoM ebiten.GeoM
+	geoM.Scale(1/s.scaleFactor, 1/s.scaleFactor)
+	geoM.Concat(opts.GeoM)
+	geoM.Scale(s.scaleFactor, s.scaleFactor)
+	opts.GeoM = geoM
 	s.Screen.DrawRectShader(ww, hh, shader, opts)
 }
 
+// NewImage returns an offscreen image for drawing a w by h area of the game
+// at the screen's scale.
+func (s *ScaledScreen) NewImage(w, h int) *ebiten.Image {
+	return ebiten.NewImage(int(float64(w)*s.scaleFactor), int(float64(h)*s.scaleFactor))
+}
+
 func (s *ScaledScreen) scaledTextSize(size float64) float64 {
 	return size * s.scaleFactor
 }
@@ -235,6 +248,5 @@ func (s *ScaledScreen) DebugPrint(str string) {
 }
 
 func AdjustedCursorPosition() (float64, float64) {
-	cx, cy := ebiten.CursorPosition()
-	return float64(cx) / ebiten.Monitor().DeviceScaleFactor(), float64(cy) / ebiten.Monitor().DeviceScaleFactor()
+	return adjustPosition(ebiten.CursorPosition())
 }
@@ -1,40 +1,95 @@
 package ui
 
-import "image/color"
+import (
+	"image/color"
+	"time"
+	"unicode/utf8"
+)
 
 type SetSprite struct {
 	SpriteName string
 	X, Y       float64
+	// Tween is the glide the sprite is in the middle of, if any.
+	Tween *Tween
 }
 
 const SetSpriteWidth = 70
 const SetSpriteHeight = 36
 
+// textSize shrinks the name of sets with more than three letters so it still
+// fits inside the sprite.
+func (s *SetSprite) textSize() float64 {
+	n := utf8.RuneCountInString(s.SpriteName)
+	if n <= 3 {
+		return 32
+	}
+	return 32 * 3 / float64(n)
+}
+
 func (s *SetSprite) Update() {
-	// This is a placeholder for any update logic needed for the sprite.
-	// Currently, it does nothing.
+	if s.Tween == nil {
+		return
+	}
+	done := s.Tween.Update()
+	s.X, s.Y = s.Tween.Position()
+	if done {
+		s.Tween = nil
+	}
 }
 func (s *SetSprite) Draw(screen *ScaledScreen) {
 	screen.DrawRect(float64(s.X), float64(s.Y), SetSpriteWidth, SetSpriteHeight, color.RGBA{255, 255, 255, 255})
-	screen.DrawTextCenteredAt(s.SpriteName, 32, int(s.X+SetSpriteWidth/2), int(s.Y+SetSpriteHeight/2), color.Black)
+	screen.DrawTextCenteredAt(s.SpriteName, s.textSize(), int(s.X+SetSpriteWidth/2), int(s.Y+SetSpriteHeight/2), color.Black)
 	screen.DrawUnfilledRect(float64(s.X), float64(s.Y), SetSpriteWidth, SetSpriteHeight, 4, color.RGBA{50, 60, 55, 255})
 }
 func (s *SetSprite) DrawWithColors(screen *ScaledScreen, colors []color.Color) {
 	screen.DrawRect(float64(s.X), float64(s.Y), SetSpriteWidth, SetSpriteHeight, color.RGBA{255, 255, 255, 255})
-	screen.DrawTextCenteredAtWithColors(s.SpriteName, 32, int(s.X+SetSpriteWidth/2), int(s.Y+SetSpriteHeight/2), colors)
+	screen.DrawTextCenteredAtWithColors(s.SpriteName, s.textSize(), int(s.X+SetSpriteWidth/2), int(s.Y+SetSpriteHeight/2), colors)
 	screen.DrawUnfilledRect(float64(s.X), float64(s.Y), SetSpriteWidth, SetSpriteHeight, 4, color.RGBA{50, 60, 55, 255})
 }
 
+// MoveTo and MoveBy move the sprite at once, stopping any glide.
 func (s *SetSprite) MoveTo(x, y float64) {
+	s.Tween = nil
 	s.X = x
 	s.Y = y
 }
 
 func (s *SetSprite) MoveBy(dx, dy float64) {
+	s.Tween = nil
 	s.X += dx
 	s.Y += dy
 }
 
+// GlideTo animates the sprite from where it is to x, y and returns the tween,
+// or nil if the sprite is already there. A glide to where the sprite is
+// already headed is left alone.
+func (s *SetSprite) GlideTo(x, y float64, duration time.Duration, easing EasingFunc) *Tween {
+	if s.Tween != nil && s.Tween.ToX == x && s.Tween.ToY == y {
+		return s.Tween
+	}
+	if s.Tween == nil && s.X == x && s.Y == y {
+		return nil
+	}
+	s.Tween = NewTween(s.X, s.Y, x, y, duration, easing)
+	return s.Tween
+}
+
+// Destination returns where the sprite ends up once its glide is over.
+func (s *SetSprite) Destination() (float64, float64) {
+	if s.Tween != nil {
+		return s.Tween.ToX, s.Tween.ToY
+	}
+	return s.X, s.Y
+}
+
+// FinishGlide moves the sprite straight to the end of its glide.
+func (s *SetSprite) FinishGlide() {
+	if s.Tween != nil {
+		s.Tween.Finish()
+		s.Update()
+	}
+}
+
 func (s *SetSprite) Contains(x, y float64) bool {
 	return x >= s.X && x < s.X+SetSpriteWidth && y >= s.Y && y < s.Y+SetSpriteHeight
 }
@@ -1,5 +1,7 @@
 package ui
 
+import "math"
+
 type StrokeDraggable interface {
 	MoveBy(dx, dy float64)
 	MoveTo(x, y float64)
@@ -55,6 +57,11 @@ func (s *Stroke) Release() {
 	s.Released = true
 }
 
+// Distance returns how far the stroke has moved since it started.
+func (s *Stroke) Distance() float64 {
+	return math.Hypot(s.currentX-s.initX, s.currentY-s.initY)
+}
+
 func (s *Stroke) PositionDiff() (float64, float64) {
 	dx := s.currentX - s.prevX
 	dy := s.currentY - s.prevY
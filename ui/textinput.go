@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+type TextInput struct {
+	X, Y, W, H float64
+	Label      string
+	Text       string
+	MaxLength  int
+	Active     bool
+
+	runes []rune
+}
+
+func NewTextInput(x, y, w, h float64, label string, maxLength int) *TextInput {
+	return &TextInput{
+		X:         x,
+		Y:         y,
+		W:         w,
+		H:         h,
+		Label:     label,
+		MaxLength: maxLength,
+	}
+}
+
+func (t *TextInput) Open() {
+	t.Text = ""
+	t.Active = true
+}
+
+func (t *TextInput) Close() {
+	t.Active = false
+}
+
+// Update reads typed characters and backspace into Text. It returns true when
+// Enter is pressed.
+func (t *TextInput) Update() bool {
+	if !t.Active {
+		return false
+	}
+	t.runes = ebiten.AppendInputChars(t.runes[:0])
+	for _, r := range t.runes {
+		if len([]rune(t.Text)) < t.MaxLength {
+			t.Text += string(r)
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(t.Text) > 0 {
+		r := []rune(t.Text)
+		t.Text = string(r[:len(r)-1])
+	}
+	return inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter)
+}
+
+func (t *TextInput) Draw(screen *ScaledScreen) {
+	if !t.Active {
+		return
+	}
+	screen.DrawRect(t.X, t.Y, t.W, t.H, color.RGBA{255, 255, 255, 255})
+	screen.DrawUnfilledRect(t.X, t.Y, t.W, t.H, 4, color.RGBA{50, 60, 55, 255})
+	screen.DrawTextCenteredAt(t.Label, 24, int(t.X+t.W/2), int(t.Y+t.H/4), color.RGBA{90, 90, 90, 255})
+	screen.DrawTextCenteredAt(strings.ToUpper(t.Text)+"_", 40, int(t.X+t.W/2), int(t.Y+t.H*5/8), color.Black)
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// An EasingFunc maps the linear progress of a tween, from 0 to 1, to how far
+// along the animated value is.
+type EasingFunc func(t float64) float64
+
+func Linear(t float64) float64 {
+	return t
+}
+
+func EaseOutCubic(t float64) float64 {
+	return 1 - math.Pow(1-t, 3)
+}
+
+func EaseInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	return 1 - math.Pow(-2*t+2, 3)/2
+}
+
+// EaseOutBack overshoots the end a little before settling.
+func EaseOutBack(t float64) float64 {
+	const c1 = 1.70158
+	const c3 = c1 + 1
+	return 1 + c3*math.Pow(t-1, 3) + c1*math.Pow(t-1, 2)
+}
+
+// EaseOutBounce lands on the end and bounces a few times.
+func EaseOutBounce(t float64) float64 {
+	const n1 = 7.5625
+	const d1 = 2.75
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	}
+	t -= 2.625 / d1
+	return n1*t*t + 0.984375
+}
+
+// Tween animates a point from one position to another over Duration.
+type Tween struct {
+	FromX, FromY float64
+	ToX, ToY     float64
+	Duration     time.Duration
+	Easing       EasingFunc
+	// OnComplete is called once, on the update the tween finishes.
+	OnComplete func()
+	elapsed    time.Duration
+}
+
+func NewTween(fromX, fromY, toX, toY float64, duration time.Duration, easing EasingFunc) *Tween {
+	return &Tween{
+		FromX:    fromX,
+		FromY:    fromY,
+		ToX:      toX,
+		ToY:      toY,
+		Duration: duration,
+		Easing:   easing,
+	}
+}
+
+// Update advances the tween by one tick and returns true once it is done.
+func (t *Tween) Update() bool {
+	if t.Done() {
+		return true
+	}
+	t.elapsed += time.Second / time.Duration(ebiten.TPS())
+	if t.Done() && t.OnComplete != nil {
+		t.OnComplete()
+	}
+	return t.Done()
+}
+
+func (t *Tween) Done() bool {
+	return t.elapsed >= t.Duration
+}
+
+// Finish skips to the end of the tween.
+func (t *Tween) Finish() {
+	if !t.Done() {
+		t.elapsed = t.Duration
+		if t.OnComplete != nil {
+			t.OnComplete()
+		}
+	}
+}
+
+// Position returns the current value of the tween.
+func (t *Tween) Position() (float64, float64) {
+	if t.Done() {
+		return t.ToX, t.ToY
+	}
+	p := float64(t.elapsed) / float64(t.Duration)
+	if t.Easing != nil {
+		p = t.Easing(p)
+	}
+	return t.FromX + (t.ToX-t.FromX)*p, t.FromY + (t.ToY-t.FromY)*p
+}
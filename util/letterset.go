@@ -0,0 +1,146 @@
+package util
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// LetterSet is a set of up to MAX_LETTERS letters stored as a bitmask. Which
+// letter each bit stands for is up to an Alphabet.
+type LetterSet uint32
+
+func (l LetterSet) Union(o LetterSet) LetterSet {
+	return l | o
+}
+
+func (l LetterSet) Intersection(o LetterSet) LetterSet {
+	return l & o
+}
+
+// Difference returns the letters of l that are not in o.
+func (l LetterSet) Difference(o LetterSet) LetterSet {
+	return l &^ o
+}
+
+func (l LetterSet) IsSubsetOf(o LetterSet) bool {
+	return l&^o == 0
+}
+
+func (l LetterSet) Len() int {
+	return bits.OnesCount32(uint32(l))
+}
+
+// String returns the bit indices of the set, such as "{0 3 5}". Use
+// Alphabet.Format to get the letters back.
+func (l LetterSet) String() string {
+	indices := make([]string, 0, l.Len())
+	for i := 0; l != 0; i++ {
+		if l&1 == 1 {
+			indices = append(indices, strconv.Itoa(i))
+		}
+		l >>= 1
+	}
+	return "{" + strings.Join(indices, " ") + "}"
+}
+
+func LetterSetsUnion(sets ...LetterSet) LetterSet {
+	var out LetterSet
+	for _, s := range sets {
+		out |= s
+	}
+	return out
+}
+
+// MAX_LETTERS is the largest alphabet that fits in a LetterSet.
+const MAX_LETTERS = 32
+
+// Alphabet maps up to MAX_LETTERS runes onto the bits of a LetterSet, so that
+// sets can be made of digits or symbols as well as the letters A-Z.
+type Alphabet struct {
+	runes []rune
+	index map[rune]int
+}
+
+func NewAlphabet(letters string) (Alphabet, error) {
+	a := Alphabet{index: make(map[rune]int)}
+	for _, r := range letters {
+		if _, ok := a.index[r]; ok {
+			return Alphabet{}, fmt.Errorf("letter %q appears twice in alphabet", r)
+		}
+		a.index[r] = len(a.runes)
+		a.runes = append(a.runes, r)
+	}
+	if len(a.runes) > MAX_LETTERS {
+		return Alphabet{}, fmt.Errorf("alphabet has %d letters, at most %d are supported", len(a.runes), MAX_LETTERS)
+	}
+	return a, nil
+}
+
+// AlphabetOf returns the alphabet of every distinct rune in strs, in order of
+// first appearance.
+func AlphabetOf(strs ...string) (Alphabet, error) {
+	seen := make(map[rune]bool)
+	letters := []rune{}
+	for _, s := range strs {
+		for _, r := range s {
+			if !seen[r] {
+				seen[r] = true
+				letters = append(letters, r)
+			}
+		}
+	}
+	return NewAlphabet(string(letters))
+}
+
+func (a Alphabet) Len() int {
+	return len(a.runes)
+}
+
+func (a Alphabet) Runes() []rune {
+	return a.runes
+}
+
+func (a Alphabet) Bit(r rune) LetterSet {
+	if i, ok := a.index[r]; ok {
+		return 1 << i
+	}
+	return 0
+}
+
+// Has reports whether r is in l. Runes outside the alphabet are never in a set.
+func (a Alphabet) Has(l LetterSet, r rune) bool {
+	b := a.Bit(r)
+	return b != 0 && l&b == b
+}
+
+// Covers reports whether every rune of s is part of the alphabet.
+func (a Alphabet) Covers(s string) bool {
+	for _, r := range s {
+		if _, ok := a.index[r]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Set returns the letters of s as a LetterSet, ignoring runes outside the alphabet.
+func (a Alphabet) Set(s string) LetterSet {
+	var l LetterSet
+	for _, r := range s {
+		l |= a.Bit(r)
+	}
+	return l
+}
+
+// Format returns the letters of l in alphabet order.
+func (a Alphabet) Format(l LetterSet) string {
+	out := make([]rune, 0, l.Len())
+	for i, r := range a.runes {
+		if l&(1<<i) != 0 {
+			out = append(out, r)
+		}
+	}
+	return string(out)
+}
@@ -110,6 +110,26 @@ func Choice[T any](s []T, filter func(T) bool, rand *rand.Rand) T {
 	return ret
 }
 
+// WeightedIndex returns an index into weights, picked with probability
+// proportional to its weight.
+func WeightedIndex(weights []int, rand *rand.Rand) int {
+	total := 0
+	for _, w := range weights {
+		total += max(w, 0)
+	}
+	if total == 0 {
+		panic("Cannot make choice with no positive weights")
+	}
+	n := rand.IntN(total)
+	for i, w := range weights {
+		n -= max(w, 0)
+		if n < 0 {
+			return i
+		}
+	}
+	return len(weights) - 1
+}
+
 func MinItem[T any](s []T, f func(t T) int) T {
 	val := math.MaxInt
 	var best T